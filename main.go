@@ -1,20 +1,130 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"oslib/oslib"
+	"strings"
+	"syscall"
+	"time"
 )
 
+const usage = `Usage: go run main.go <command> [flags]
+
+Commands:
+  dashboard     Generate user_dashboard: assigned issues, created issues and PRs per user
+  issues        Generate a page, Atom and JSON feeds for every configured label
+  achievements  Generate team_achievements: each user's activity grouped by month
+  kubernetes    Generate kubernetes_contributions: PRs to kubernetes and kubernetes-sigs
+  teams         Generate teams: each configured team's totals and an org-wide total
+  users         Generate user_changes: who was added to or removed from the tracked users
+  cycletime     Generate pr_cycle_time: time to first review, time to merge and review rounds
+  stale         Generate needs_attention: stale open PRs and assigned issues, oldest first
+  leaderboard   Generate leaderboard: users ranked by weighted activity points
+  all           Generate every report from a single fetch of the shared data
+  serve         Serve every report over HTTP, refreshing them in the background
+  config validate  Check the config file and that every user and org exists on GitHub
+
+Run "go run main.go <command> -help" to see the flags of a command.
+The GITHUB_TOKEN environment variable must hold a GitHub token.
+The config may be JSON or YAML; TRACKER_USERS, TRACKER_ORGS, TRACKER_LABELS,
+TRACKER_SITE_URL, TRACKER_FEEDS_PER_ORG, TRACKER_GITHUB_TEAMS and
+TRACKER_EXCLUDE_USERS override its values.
+`
+
+var commands = map[string]func(args []string){
+	"dashboard":    runDashboard,
+	"issues":       runIssues,
+	"achievements": runAchievements,
+	"kubernetes":   runKubernetes,
+	"teams":        runTeams,
+	"users":        runUsers,
+	"cycletime":    runCycleTime,
+	"stale":        runStale,
+	"leaderboard":  runLeaderboard,
+	"all":          runAll,
+	"serve":        runServe,
+	"config":       runConfig,
+}
+
 func main() {
-	showIssues := flag.Bool("issues", false, "Fetch only good first issues")
-	showMonthlyReport := flag.Bool("monthlyreport", false, "Fetch only good first issues")
-	showKubernetes := flag.Bool("kubernetes", false, "Fetch only kubernetes contributions")
-	flag.Parse()
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return
+	}
+
+	run, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+	run(os.Args[2:])
+}
 
-	configPath := "config.json"
-	config, err := oslib.LoadConfig(configPath)
+// env is what every command needs once its flags are parsed.
+type env struct {
+	config *oslib.Config
+	token  string
+	out    oslib.Output
+}
+
+// commonFlags are accepted by every command; outputDir and format only by those writing files.
+type commonFlags struct {
+	config    *string
+	outputDir *string
+	format    *string
+}
+
+func newFlagSet(name, summary string) (*flag.FlagSet, commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: go run main.go %s [flags]\n\n%s\n\nFlags:\n", name, summary)
+		fs.PrintDefaults()
+	}
+	return fs, commonFlags{
+		config: fs.String("config", "config.json", "Path to the config file"),
+	}
+}
+
+func newOutputFlagSet(name, summary string) (*flag.FlagSet, commonFlags) {
+	fs, common := newFlagSet(name, summary)
+	common.outputDir = fs.String("output-dir", "docs", `Directory reports are written to, or "-" for standard output`)
+	common.format = fs.String("format", "html", "Output format: "+strings.Join(oslib.Formats, ", "))
+	return fs, common
+}
+
+// parse parses args and loads everything the command needs, exiting on any error.
+func parse(fs *flag.FlagSet, common commonFlags, args []string) env {
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "Unexpected arguments: %s\n\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var out oslib.Output
+	if common.format != nil {
+		renderer, err := oslib.NewRenderer(*common.format)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out = oslib.Output{Renderer: renderer, Dir: *common.outputDir}
+	}
+
+	config, err := oslib.LoadConfig(*common.config)
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
@@ -23,14 +133,209 @@ func main() {
 	if githubToken == "" {
 		log.Fatal("Environment variable GITHUB_TOKEN is required")
 	}
-	if *showIssues {
-		oslib.GenerateIssuesReport(config.Orgs, githubToken, config.Labels)
-	} else if *showMonthlyReport {
-		oslib.GenerateTeamAchievements(config.Users, githubToken)
-	} else if *showKubernetes {
-		oslib.GenerateKubernetesContributions(config.Users, githubToken)
-	} else {
-		oslib.GenerateReport(config.Users, githubToken)
+
+	return env{
+		config: config,
+		token:  githubToken,
+		out:    out,
+	}
+}
+
+// usersFlag registers -users, which narrows a run to some of the configured users.
+func usersFlag(fs *flag.FlagSet) *string {
+	return fs.String("users", "", "Comma-separated GitHub logins to report on instead of every configured user")
+}
+
+// selectUsers is nil, meaning every configured user, unless -users narrowed the run.
+func selectUsers(only string) []string {
+	if only == "" {
+		return nil
+	}
+	return strings.Split(only, ",")
+}
+
+// delayFlag registers -delay, the pause between users that keeps a run under the search rate limit.
+func delayFlag(fs *flag.FlagSet, value time.Duration) *time.Duration {
+	return fs.Duration("delay", value, "Pause between users to avoid rate-limiting")
+}
+
+func runDashboard(args []string) {
+	fs, common := newOutputFlagSet("dashboard", "Generate the per-user issue and PR dashboard.")
+	users := usersFlag(fs)
+	delay := delayFlag(fs, 30*time.Second)
+	e := parse(fs, common, args)
+
+	oslib.Generate(e.config, selectUsers(*users), e.token, *delay, []string{oslib.PageDashboard}, e.out)
+}
+
+func runIssues(args []string) {
+	fs, common := newOutputFlagSet("issues", "Generate a page and feeds for every configured label.")
+	labels := fs.String("labels", "", "Comma-separated label names to generate instead of every configured label")
+	e := parse(fs, common, args)
+
+	if *labels != "" {
+		e.config.Labels = selectLabels(e.config.Labels, strings.Split(*labels, ","))
+	}
+	oslib.Generate(e.config, nil, e.token, 0, []string{oslib.PageIssues}, e.out)
+}
+
+func selectLabels(configured []oslib.Label, names []string) []oslib.Label {
+	var selected []oslib.Label
+	for _, name := range names {
+		label := oslib.Label{Name: name}
+		for _, l := range configured {
+			if l.Name == name {
+				label = l
+			}
+		}
+		selected = append(selected, label)
+	}
+	return selected
+}
+
+func runAchievements(args []string) {
+	fs, common := newOutputFlagSet("achievements", "Generate the monthly team achievements report.")
+	users := usersFlag(fs)
+	delay := delayFlag(fs, 0)
+	e := parse(fs, common, args)
+
+	oslib.Generate(e.config, selectUsers(*users), e.token, *delay, []string{oslib.PageAchievements}, e.out)
+}
+
+func runKubernetes(args []string) {
+	fs, common := newOutputFlagSet("kubernetes", "Generate the Kubernetes contributions report.")
+	users := usersFlag(fs)
+	delay := delayFlag(fs, 0)
+	e := parse(fs, common, args)
+
+	oslib.Generate(e.config, selectUsers(*users), e.token, *delay, []string{oslib.PageKubernetes}, e.out)
+}
+
+func runTeams(args []string) {
+	fs, common := newOutputFlagSet("teams", "Generate the summary of every configured team.")
+	delay := delayFlag(fs, 30*time.Second)
+	e := parse(fs, common, args)
+
+	if len(e.config.Teams) == 0 {
+		log.Fatal("The config has no teams")
+	}
+	oslib.Generate(e.config, nil, e.token, *delay, []string{oslib.PageTeams}, e.out)
+}
+
+// runUsers resolves the tracked users, GitHub team members included, and
+// compares them with the previous run recorded in the output directory.
+func runUsers(args []string) {
+	fs, common := newOutputFlagSet("users", "Generate the list of users added or removed since the previous run.")
+	e := parse(fs, common, args)
+
+	oslib.Generate(e.config, nil, e.token, 0, []string{oslib.PageUserChanges}, e.out)
+}
+
+func runCycleTime(args []string) {
+	fs, common := newOutputFlagSet("cycletime", "Generate the PR cycle time report.")
+	users := usersFlag(fs)
+	delay := delayFlag(fs, 30*time.Second)
+	e := parse(fs, common, args)
+
+	if !e.config.CycleTime {
+		log.Fatal("The cycle time report is off; set cycle_time: true in the config to turn it on")
 	}
+	oslib.Generate(e.config, selectUsers(*users), e.token, *delay, []string{oslib.PageCycleTime}, e.out)
+}
+
+func runStale(args []string) {
+	fs, common := newOutputFlagSet("stale", "Generate the page of stale open PRs and assigned issues.")
+	users := usersFlag(fs)
+	delay := delayFlag(fs, 30*time.Second)
+	e := parse(fs, common, args)
+
+	oslib.Generate(e.config, selectUsers(*users), e.token, *delay, []string{oslib.PageStale}, e.out)
+}
+
+func runLeaderboard(args []string) {
+	fs, common := newOutputFlagSet("leaderboard", "Generate the leaderboard of users ranked by weighted activity points.")
+	users := usersFlag(fs)
+	delay := delayFlag(fs, 30*time.Second)
+	e := parse(fs, common, args)
 
+	if !e.config.ScoringEnabled() {
+		log.Fatal("The leaderboard is off; add a scoring section to the config to turn it on")
+	}
+	oslib.Generate(e.config, selectUsers(*users), e.token, *delay, []string{oslib.PageLeaderboard}, e.out)
+}
+
+// runAll fetches the union of what every report needs once, then renders them all from that data.
+func runAll(args []string) {
+	fs, common := newOutputFlagSet("all", "Generate every report from a single fetch.")
+	users := usersFlag(fs)
+	delay := delayFlag(fs, 30*time.Second)
+	e := parse(fs, common, args)
+
+	oslib.Generate(e.config, selectUsers(*users), e.token, *delay, oslib.Reports, e.out)
+}
+
+func runServe(args []string) {
+	fs, common := newFlagSet("serve", "Serve every report over HTTP and refresh them in the background.\nSet TRACKER_REFRESH_TOKEN to let POST /refresh with that bearer token trigger a refresh right away; GET /status reports progress.\nSet GITHUB_WEBHOOK_SECRET to apply GitHub webhook deliveries sent to POST /webhook as they arrive.")
+	users := usersFlag(fs)
+	delay := delayFlag(fs, 30*time.Second)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	interval := fs.Duration("interval", time.Hour, "How often to refresh the reports")
+	e := parse(fs, common, args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	webhookSecret := os.Getenv(oslib.WebhookSecretEnv)
+	if webhookSecret != "" {
+		log.Printf("Accepting GitHub webhooks on POST /webhook")
+	}
+	refreshToken := os.Getenv(oslib.RefreshTokenEnv)
+	if refreshToken != "" {
+		log.Printf("Accepting refresh requests on POST /refresh")
+	}
+	server := oslib.NewServer(e.config, selectUsers(*users), e.token, *delay, *interval, webhookSecret, refreshToken)
+	go server.Run(ctx)
+
+	httpServer := &http.Server{Addr: *addr, Handler: server.Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Serving reports on %s, refreshing every %s", *addr, *interval)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+func runConfig(args []string) {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprint(os.Stderr, "Usage: go run main.go config validate [flags]\n")
+		os.Exit(2)
+	}
+
+	fs, common := newFlagSet("config validate", "Check the config file, then check that every user and org exists on GitHub.")
+	offline := fs.Bool("offline", false, "Only check the file, without asking GitHub")
+	fs.Parse(args[1:])
+
+	config, err := oslib.LoadConfig(*common.config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if !*offline {
+		problems, err := oslib.CheckConfigOnGitHub(context.Background(), config, os.Getenv("GITHUB_TOKEN"))
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", *common.config, problem)
+		}
+		if err != nil {
+			log.Fatalf("Error checking config on GitHub: %v", err)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+	}
+	fmt.Printf("%s is valid: %d users, %d orgs, %d labels\n", *common.config, len(config.Users), len(config.Orgs), len(config.Labels))
 }
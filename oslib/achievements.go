@@ -6,78 +6,138 @@ import (
 )
 
 type Activity struct {
-	Title     string
-	URL       string
-	Repo      string
-	Timestamp time.Time
-	Action    string
+	User      string    `json:"user"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	Repo      string    `json:"repo"`
+	Owner     string    `json:"owner,omitempty"`
+	Labels    []string  `json:"labels,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Action    string    `json:"action"`
+	// Detail qualifies the action, such as a review's outcome.
+	Detail string `json:"detail,omitempty"`
+	// Class is the work class of the repo, when classes are configured.
+	Class string `json:"class,omitempty"`
 }
 
-// FetchMonthlyActivity gathers all PR and Issue activities for a list of users
-func FetchMonthlyActivity(users []string, token string) map[string][]Activity {
-	activityByUser := make(map[string][]Activity)
-
-	for _, user := range users {
-		var activities []Activity
-
-		// 1. Open PRs
-		for _, pr := range FetchOpenPRs(user, token) {
-			t, _ := time.Parse(time.RFC3339, pr.CreatedAt)
-			activities = append(activities, Activity{
-				Title:     pr.Title,
-				URL:       pr.URL,
-				Repo:      pr.Repo,
-				Timestamp: t,
-				Action:    "opened_pr",
-			})
-		}
+// userActivities turns one user's fetched PRs and issues into activities.
+func userActivities(data *UserData) []Activity {
+	var activities []Activity
+
+	// 1. Open PRs
+	for _, pr := range data.OpenPRs {
+		t, _ := time.Parse(time.RFC3339, pr.CreatedAt)
+		activities = append(activities, Activity{
+			Title:     pr.Title,
+			URL:       pr.URL,
+			Repo:      pr.Repo,
+			Owner:     pr.Owner,
+			Labels:    pr.LabelNames(),
+			Timestamp: t,
+			Action:    "opened_pr",
+		})
+	}
 
-		// 2. Closed PRs
-		for _, pr := range FetchClosedPRs(user, token) {
-			t, _ := time.Parse(time.RFC3339, pr.CreatedAt)
-			activities = append(activities, Activity{
-				Title:     pr.Title,
-				URL:       pr.URL,
-				Repo:      pr.Repo,
-				Timestamp: t,
-				Action:    "closed_pr",
-			})
-		}
+	// 2. Closed PRs, on the day they were merged or closed
+	for _, pr := range data.ClosedPRs {
+		t, _ := time.Parse(time.RFC3339, pr.closedOn())
+		activities = append(activities, Activity{
+			Title:     pr.Title,
+			URL:       pr.URL,
+			Repo:      pr.Repo,
+			Owner:     pr.Owner,
+			Labels:    pr.LabelNames(),
+			Timestamp: t,
+			Action:    "closed_pr",
+		})
+	}
 
-		// 3. Open Created Issues
-		for _, issue := range FetchCreatedIssues(user, token) {
-			t, _ := time.Parse(time.RFC3339, issue.CreatedAt)
-			activities = append(activities, Activity{
-				Title:     issue.Title,
-				URL:       issue.URL,
-				Repo:      issue.Repo,
-				Timestamp: t,
-				Action:    "created_issue_open",
-			})
-		}
+	// 3. Open Created Issues
+	for _, issue := range data.CreatedIssues {
+		t, _ := time.Parse(time.RFC3339, issue.CreatedAt)
+		activities = append(activities, Activity{
+			Title:     issue.Title,
+			URL:       issue.URL,
+			Repo:      issue.Repo,
+			Owner:     issue.Owner,
+			Labels:    issue.LabelNames(),
+			Timestamp: t,
+			Action:    "created_issue_open",
+		})
+	}
 
-		// 4. Closed Created Issues
-		for _, issue := range FetchClosedIssues(user, token) {
-			t, _ := time.Parse(time.RFC3339, issue.CreatedAt)
-			activities = append(activities, Activity{
-				Title:     issue.Title,
-				URL:       issue.URL,
-				Repo:      issue.Repo,
-				Timestamp: t,
-				Action:    "created_issue_closed",
-			})
-		}
+	// 4. Closed Created Issues
+	for _, issue := range data.ClosedIssues {
+		t, _ := time.Parse(time.RFC3339, issue.CreatedAt)
+		activities = append(activities, Activity{
+			Title:     issue.Title,
+			URL:       issue.URL,
+			Repo:      issue.Repo,
+			Owner:     issue.Owner,
+			Labels:    issue.LabelNames(),
+			Timestamp: t,
+			Action:    "created_issue_closed",
+		})
+	}
+
+	// 5. Reviews of other people's PRs
+	for _, r := range data.Reviews {
+		t, _ := time.Parse(time.RFC3339, r.SubmittedAt)
+		activities = append(activities, Activity{
+			Title:     r.PR.Title,
+			URL:       r.PR.URL,
+			Repo:      r.PR.Repo,
+			Owner:     r.PR.Owner,
+			Labels:    r.PR.LabelNames(),
+			Timestamp: t,
+			Action:    "reviewed_pr",
+			Detail:    r.StateLabel(),
+		})
+	}
 
-		activityByUser[user] = activities
+	// 6. Comments on issue and PR threads
+	for _, c := range data.Comments {
+		t, _ := time.Parse(time.RFC3339, c.CreatedAt)
+		activities = append(activities, Activity{
+			Title:     c.Issue.Title,
+			URL:       c.URL,
+			Repo:      c.Issue.Repo,
+			Owner:     c.Issue.Owner,
+			Labels:    c.Issue.LabelNames(),
+			Timestamp: t,
+			Action:    "commented",
+		})
+	}
+
+	// 7. Authored and co-authored commits
+	for _, c := range data.Commits {
+		t, _ := time.Parse(time.RFC3339, c.Date)
+		a := Activity{
+			Title:     c.Message,
+			URL:       c.URL,
+			Repo:      c.Repo,
+			Owner:     c.Owner,
+			Timestamp: t,
+			Action:    "committed",
+			Detail:    c.ShortSHA(),
+		}
+		if c.CoAuthored {
+			a.Detail += ", co-authored"
+		}
+		activities = append(activities, a)
 	}
 
-	return activityByUser
+	return activities
 }
 
 type MonthlyUserActivity struct {
 	User       string
+	Name       string
+	Avatar     string `json:",omitempty"`
 	Month      string // "2025-07"
 	Activities []Activity
+	// Classes counts the activities per work class, when classes are configured.
+	Classes []ClassCount `json:",omitempty"`
 }
 
 func GroupMonthlyActivity(activityByUser map[string][]Activity) (map[string]map[string][]Activity, []string) {
@@ -0,0 +1,364 @@
+package oslib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The read-only JSON API served next to the HTML reports. It reads the same
+// Dataset the reports are rendered from, narrowed by the default filter
+// profile; reports given another profile in report_filters can differ from it.
+//
+//	GET /api/users                          tracked users with their counts
+//	GET /api/users/{login}                  one user's issue and PR buckets
+//	GET /api/users/{login}/prs?state=       open, closed, merged or all (default) PRs
+//	GET /api/users/{login}/issues?type=     assigned, created, closed or all (default) issues
+//	GET /api/activity?from=&to=&user=&action=
+//	GET /api/issues?label=&org=
+//
+// List endpoints take page (from 1) and per_page (at most 100) and wrap results in apiPage.
+
+const (
+	apiDefaultPerPage = 30
+	apiMaxPerPage     = 100
+)
+
+type apiPage struct {
+	Items      interface{} `json:"items"`
+	Page       int         `json:"page"`
+	PerPage    int         `json:"per_page"`
+	TotalCount int         `json:"total_count"`
+	FetchedAt  time.Time   `json:"fetched_at"`
+}
+
+type apiUser struct {
+	Login          string `json:"login"`
+	Name           string `json:"name"`
+	AssignedIssues int    `json:"assigned_issues"`
+	CreatedIssues  int    `json:"created_issues"`
+	OpenPRs        int    `json:"open_prs"`
+	ClosedPRs      int    `json:"closed_prs"`
+	MergedPRs      int    `json:"merged_prs"`
+	Reviews        int    `json:"reviews"`
+	Comments       int    `json:"comments"`
+}
+
+type apiUserDetail struct {
+	Login          string        `json:"login"`
+	Name           string        `json:"name"`
+	Aliases        []string      `json:"aliases,omitempty"`
+	Avatar         string        `json:"avatar,omitempty"`
+	AssignedIssues []Issue       `json:"assigned_issues"`
+	CreatedIssues  []Issue       `json:"created_issues"`
+	ClosedIssues   []Issue       `json:"closed_issues"`
+	OpenPRs        []Issue       `json:"open_prs"`
+	ClosedPRs      []Issue       `json:"closed_prs"`
+	Reviews        []Review      `json:"reviews"`
+	Comments       []Comment     `json:"comments"`
+	Commits        []Commit      `json:"commits"`
+	WaitingOnMe    []WaitingItem `json:"waiting_on_me"`
+}
+
+type apiIssue struct {
+	Issue
+	Org   string `json:"org"`
+	Label string `json:"label"`
+	// LikelyTaken is the comment claiming the issue, if any.
+	LikelyTaken *Claim `json:"likely_taken,omitempty"`
+}
+
+// apiError is the body of every non-2xx API response.
+type apiError struct {
+	Message string `json:"message"`
+}
+
+func (s *Server) registerAPI(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/users", s.apiHandler(s.handleAPIUsers))
+	mux.HandleFunc("GET /api/users/{login}", s.apiHandler(s.handleAPIUser))
+	mux.HandleFunc("GET /api/users/{login}/prs", s.apiHandler(s.handleAPIUserPRs))
+	mux.HandleFunc("GET /api/users/{login}/issues", s.apiHandler(s.handleAPIUserIssues))
+	mux.HandleFunc("GET /api/activity", s.apiHandler(s.handleAPIActivity))
+	mux.HandleFunc("GET /api/issues", s.apiHandler(s.handleAPIIssues))
+}
+
+// apiHandler turns a handler returning a value or an error into a JSON endpoint.
+func (s *Server) apiHandler(h func(d *Dataset, r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d := s.apiDataset()
+		if d == nil {
+			w.Header().Set("Retry-After", "60")
+			writeJSON(w, http.StatusServiceUnavailable, apiError{"data is still being fetched, try again shortly"})
+			return
+		}
+		v, err := h(d, r)
+		if err != nil {
+			status := http.StatusBadRequest
+			if se, ok := err.(apiStatusError); ok {
+				status = se.status
+			}
+			writeJSON(w, status, apiError{err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, v)
+	}
+}
+
+type apiStatusError struct {
+	status  int
+	message string
+}
+
+func (e apiStatusError) Error() string { return e.message }
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func (s *Server) handleAPIUsers(d *Dataset, r *http.Request) (interface{}, error) {
+	users := make([]apiUser, 0, len(d.Users))
+	for _, login := range d.Users {
+		data := d.user(login)
+		merged := 0
+		for _, pr := range data.ClosedPRs {
+			if pr.Merged() {
+				merged++
+			}
+		}
+		users = append(users, apiUser{
+			Login:          login,
+			Name:           d.person(login).DisplayName(),
+			AssignedIssues: len(data.AssignedIssues),
+			CreatedIssues:  len(data.CreatedIssues),
+			OpenPRs:        len(data.OpenPRs),
+			ClosedPRs:      len(data.ClosedPRs),
+			MergedPRs:      merged,
+			Reviews:        len(data.Reviews),
+			Comments:       len(data.Comments),
+		})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Login < users[j].Login })
+	return paginate(d, r, users)
+}
+
+func (s *Server) handleAPIUser(d *Dataset, r *http.Request) (interface{}, error) {
+	login, data, err := apiLookupUser(d, r)
+	if err != nil {
+		return nil, err
+	}
+	person := d.person(login)
+	return apiUserDetail{
+		Login:          login,
+		Name:           person.DisplayName(),
+		Aliases:        person.Aliases,
+		Avatar:         person.Avatar,
+		AssignedIssues: nonNil(data.AssignedIssues),
+		CreatedIssues:  nonNil(data.CreatedIssues),
+		ClosedIssues:   nonNil(data.ClosedIssues),
+		OpenPRs:        nonNil(data.OpenPRs),
+		ClosedPRs:      nonNil(data.ClosedPRs),
+		Reviews:        append([]Review{}, data.Reviews...),
+		Comments:       append([]Comment{}, data.Comments...),
+		Commits:        append([]Commit{}, data.Commits...),
+		WaitingOnMe:    append([]WaitingItem{}, d.waitingOnMe(login)...),
+	}, nil
+}
+
+func (s *Server) handleAPIUserPRs(d *Dataset, r *http.Request) (interface{}, error) {
+	_, data, err := apiLookupUser(d, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []Issue
+	switch state := r.URL.Query().Get("state"); state {
+	case "open":
+		prs = data.OpenPRs
+	case "closed":
+		prs = data.ClosedPRs
+	case "merged":
+		for _, pr := range data.ClosedPRs {
+			if pr.Merged() {
+				prs = append(prs, pr)
+			}
+		}
+	case "", "all":
+		prs = append(append(prs, data.OpenPRs...), data.ClosedPRs...)
+	default:
+		return nil, fmt.Errorf("state must be open, closed, merged or all, not %q", state)
+	}
+	return paginate(d, r, sortIssues(prs))
+}
+
+func (s *Server) handleAPIUserIssues(d *Dataset, r *http.Request) (interface{}, error) {
+	_, data, err := apiLookupUser(d, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	switch kind := r.URL.Query().Get("type"); kind {
+	case "assigned":
+		issues = data.AssignedIssues
+	case "created":
+		issues = data.CreatedIssues
+	case "closed":
+		issues = data.ClosedIssues
+	case "", "all":
+		issues = append(append(append(issues, data.AssignedIssues...), data.CreatedIssues...), data.ClosedIssues...)
+	default:
+		return nil, fmt.Errorf("type must be assigned, created, closed or all, not %q", kind)
+	}
+	return paginate(d, r, sortIssues(issues))
+}
+
+func (s *Server) handleAPIActivity(d *Dataset, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	from, err := apiDate(query.Get("from"), false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := apiDate(query.Get("to"), true)
+	if err != nil {
+		return nil, err
+	}
+	user := query.Get("user")
+	action := query.Get("action")
+
+	var activities []Activity
+	for _, acts := range d.Activity() {
+		for _, a := range acts {
+			if user != "" && !d.person(a.User).Has(user) {
+				continue
+			}
+			if action != "" && a.Action != action {
+				continue
+			}
+			if (!from.IsZero() && a.Timestamp.Before(from)) || (!to.IsZero() && !a.Timestamp.Before(to)) {
+				continue
+			}
+			activities = append(activities, a)
+		}
+	}
+	sort.Slice(activities, func(i, j int) bool {
+		return activities[i].Timestamp.After(activities[j].Timestamp)
+	})
+	return paginate(d, r, activities)
+}
+
+func (s *Server) handleAPIIssues(d *Dataset, r *http.Request) (interface{}, error) {
+	label := r.URL.Query().Get("label")
+	org := r.URL.Query().Get("org")
+
+	var issues []apiIssue
+	for _, l := range d.Labels {
+		if label != "" && !strings.EqualFold(l.Label.Name, label) && l.Label.Slug() != label {
+			continue
+		}
+		for labelOrg, orgIssues := range l.ByOrg {
+			if org != "" && !strings.EqualFold(labelOrg, org) {
+				continue
+			}
+			for _, issue := range orgIssues {
+				item := apiIssue{Issue: issue, Org: labelOrg, Label: l.Label.Name}
+				if claim, ok := l.Claims[issue.URL]; ok {
+					item.LikelyTaken = &claim
+				}
+				issues = append(issues, item)
+			}
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].CreatedAt > issues[j].CreatedAt
+	})
+	return paginate(d, r, issues)
+}
+
+func apiLookupUser(d *Dataset, r *http.Request) (string, *UserData, error) {
+	login := r.PathValue("login")
+	for _, user := range d.Users {
+		if d.person(user).Has(login) {
+			return user, d.user(user), nil
+		}
+	}
+	return "", nil, apiStatusError{http.StatusNotFound, fmt.Sprintf("user %q is not tracked", login)}
+}
+
+// apiDate parses a YYYY-MM-DD query value. An end date is exclusive of the following day, so to=2025-07-31 includes July 31.
+func apiDate(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("dates must look like 2006-01-02, not %q", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func paginate[T any](d *Dataset, r *http.Request, items []T) (apiPage, error) {
+	page, err := apiInt(r, "page", 1)
+	if err != nil {
+		return apiPage{}, err
+	}
+	perPage, err := apiInt(r, "per_page", apiDefaultPerPage)
+	if err != nil {
+		return apiPage{}, err
+	}
+	if perPage > apiMaxPerPage {
+		perPage = apiMaxPerPage
+	}
+
+	// Comparing pages rather than multiplying keeps a huge page from overflowing.
+	start := len(items)
+	if page-1 <= len(items)/perPage {
+		start = min((page-1)*perPage, len(items))
+	}
+	end := min(start+perPage, len(items))
+	return apiPage{
+		Items:      nonNil(items[start:end]),
+		Page:       page,
+		PerPage:    perPage,
+		TotalCount: len(items),
+		FetchedAt:  d.FetchedAt,
+	}, nil
+}
+
+func apiInt(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive number, not %q", name, value)
+	}
+	return n, nil
+}
+
+func sortIssues(issues []Issue) []Issue {
+	sorted := append([]Issue(nil), issues...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt > sorted[j].CreatedAt
+	})
+	return sorted
+}
+
+// nonNil keeps empty lists as [] rather than null in JSON.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,47 @@
+package oslib
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		query     string
+		want      []int
+		wantErr   bool
+		wantCount int
+	}{
+		{"", []int{1, 2, 3, 4, 5}, false, 5},
+		{"?per_page=2", []int{1, 2}, false, 5},
+		{"?per_page=2&page=3", []int{5}, false, 5},
+		{"?per_page=2&page=4", []int{}, false, 5},
+		{"?page=9223372036854775807&per_page=100", []int{}, false, 5},
+		{"?page=0", nil, true, 0},
+		{"?per_page=abc", nil, true, 0},
+	}
+	d := &Dataset{}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/issues"+tt.query, nil)
+		page, err := paginate(d, r, items)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("paginate(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		got := page.Items.([]int)
+		if len(got) != len(tt.want) || page.TotalCount != tt.wantCount {
+			t.Errorf("paginate(%q) = %v of %d, want %v of %d", tt.query, got, page.TotalCount, tt.want, tt.wantCount)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("paginate(%q) = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package oslib
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Claim is a comment in which someone asked to work on an issue.
+type Claim struct {
+	// By is who will work on it: the commenter, or whoever "/assign @login" names.
+	By  string `json:"by"`
+	At  string `json:"at"`
+	URL string `json:"url"`
+}
+
+// TakenIssue is an open issue that someone claimed in its comments.
+type TakenIssue struct {
+	Issue
+	Claim Claim `json:"claim"`
+}
+
+var (
+	// assignCommand is the bot command, optionally naming who to assign.
+	assignCommand   = regexp.MustCompile(`(?im)^\s*/assign\b(?:[ \t]+@?([a-z0-9-]+))?`)
+	unassignCommand = regexp.MustCompile(`(?im)^\s*/unassign\b(?:[ \t]+@?([a-z0-9-]+))?`)
+	// claimPhrase matches the usual ways of asking for an issue, e.g. "I'd like to work on this".
+	claimPhrase = regexp.MustCompile(`(?i)\b(?:i'?d (?:like|love) to|i would (?:like|love) to|i'?ll|i will|can i|could i|may i) (?:work on|take|pick up|tackle) (?:this|it)\b|\bi'?m (?:working on|on) (?:this|it)\b|\bi am working on (?:this|it)\b|\bassign (?:this|it) to me\b|\bplease assign me\b`)
+	// quoted matches what a comment quotes or shows as code, which is not
+	// the commenter speaking: quoted lines, fenced blocks and inline code.
+	quoted = regexp.MustCompile("(?m)^[ \t]*>.*$|(?s)```.*?(?:```|\\z)|`[^`\n]*`")
+)
+
+// claimed applies one comment to the issue's current claim: a claim replaces
+// it and an "/unassign" of the claimant drops it, "/unassign" alone meaning
+// the commenter. ok reports whether the issue is still claimed.
+func claimed(current Claim, ok bool, c issueComment) (Claim, bool) {
+	body := quoted.ReplaceAllString(strings.ReplaceAll(c.Body, "’", "'"), "")
+	switch {
+	case unassignCommand.MatchString(body):
+		who := c.User.Login
+		if login := unassignCommand.FindStringSubmatch(body)[1]; login != "" {
+			who = login
+		}
+		if ok && strings.EqualFold(who, current.By) {
+			return Claim{}, false
+		}
+		return current, ok
+	case assignCommand.MatchString(body):
+		by := c.User.Login
+		if login := assignCommand.FindStringSubmatch(body)[1]; login != "" {
+			by = login
+		}
+		return Claim{By: by, At: c.CreatedAt, URL: c.URL}, true
+	case claimPhrase.MatchString(body):
+		return Claim{By: c.User.Login, At: c.CreatedAt, URL: c.URL}, true
+	}
+	return current, ok
+}
+
+// fetchClaims reads the comments of every issue and returns the ones still claimed, keyed by URL.
+func fetchClaims(ctx context.Context, issues []Issue, token string) (map[string]Claim, error) {
+	claims := make(map[string]Claim)
+	for _, issue := range issues {
+		comments, err := fetchIssueComments(ctx, issue, "", token)
+		if err != nil {
+			return nil, fmt.Errorf("fetching comments of %s: %w", issue.URL, err)
+		}
+		var claim Claim
+		var ok bool
+		for _, c := range comments {
+			claim, ok = claimed(claim, ok, c)
+		}
+		if ok {
+			claims[issue.URL] = claim
+		}
+	}
+	return claims, nil
+}
+
+// applyClaim updates the claims on issue with a new comment, for every label detecting claims that lists it.
+func (d *Dataset) applyClaim(issue Issue, comment *issueComment) {
+	if comment == nil {
+		return
+	}
+	for i := range d.Labels {
+		l := &d.Labels[i]
+		if !l.Label.DetectClaims || !l.has(issue.URL) {
+			continue
+		}
+		current, ok := l.Claims[issue.URL]
+		claim, ok := claimed(current, ok, *comment)
+		if !ok {
+			delete(l.Claims, issue.URL)
+			continue
+		}
+		if l.Claims == nil {
+			l.Claims = make(map[string]Claim)
+		}
+		l.Claims[issue.URL] = claim
+	}
+}
@@ -0,0 +1,69 @@
+package oslib
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClaimed(t *testing.T) {
+	comment := func(login, body string) issueComment {
+		c := issueComment{Body: body, URL: "https://github.com/o/r/issues/1#" + login}
+		c.User.Login = login
+		return c
+	}
+	tests := []struct {
+		name     string
+		comments []issueComment
+		wantBy   string
+	}{
+		{"no comments", nil, ""},
+		{"unrelated comment", []issueComment{comment("alice", "Thanks for filing this!")}, ""},
+		{"claim phrase", []issueComment{comment("alice", "Hi, I'd like to work on this.")}, "alice"},
+		{"curly apostrophe", []issueComment{comment("alice", "I’ll take it")}, "alice"},
+		{"assign", []issueComment{comment("alice", "/assign")}, "alice"},
+		{"assign someone else", []issueComment{comment("maintainer", "/assign @bob")}, "bob"},
+		{"claim by the issue author", []issueComment{comment("author", "Filed this so I can track it; I'm working on it.")}, "author"},
+		{"later claim wins", []issueComment{comment("alice", "/assign"), comment("bob", "can I take this?")}, "bob"},
+		{"quoted claim", []issueComment{comment("bob", "> I'd like to work on this\n\nAlice, are you still on it?")}, ""},
+		{"fenced code", []issueComment{comment("bob", "Run this:\n```\n/assign\n```")}, ""},
+		{"inline code", []issueComment{comment("bob", "Comment `/assign` to take it.")}, ""},
+		{"unassign by claimant", []issueComment{comment("alice", "/assign"), comment("alice", "/unassign")}, ""},
+		{"unassign naming the claimant", []issueComment{comment("alice", "/assign"), comment("maintainer", "/unassign @alice")}, ""},
+		{"unassign by someone else", []issueComment{comment("alice", "/assign"), comment("bob", "/unassign")}, "alice"},
+		{"unassign naming someone else", []issueComment{comment("alice", "/assign"), comment("maintainer", "/unassign @bob")}, "alice"},
+		{"claim after unassign", []issueComment{comment("alice", "/assign"), comment("alice", "/unassign"), comment("bob", "/assign")}, "bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claim Claim
+			var ok bool
+			for _, c := range tt.comments {
+				claim, ok = claimed(claim, ok, c)
+			}
+			if ok != (tt.wantBy != "") || claim.By != tt.wantBy {
+				t.Errorf("claimed() = %q, %v; want %q", claim.By, ok, tt.wantBy)
+			}
+		})
+	}
+}
+
+func TestLabelIssuesQuery(t *testing.T) {
+	since := time.Now().AddDate(0, 0, -30).Format("2006-01-02")
+	tests := []struct {
+		name  string
+		label Label
+		want  string
+	}{
+		{"plain", Label{Name: "help wanted"}, `org:o label:"help wanted" is:issue state:open`},
+		{"unassigned", Label{Name: "bug", Unassigned: true}, "org:o label:bug is:issue state:open no:assignee"},
+		{"no linked pr", Label{Name: "bug", NoLinkedPR: true}, "org:o label:bug is:issue state:open -linked:pr"},
+		{"fresh", Label{Name: "bug", UpdatedWithinDays: 30}, "org:o label:bug is:issue state:open updated:>=" + since},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := labelIssuesQuery("o", tt.label).String(); got != tt.want {
+				t.Errorf("labelIssuesQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package oslib
+
+import "strings"
+
+// Work classes an org or repo can be declared as.
+const (
+	ClassInternal  = "internal"
+	ClassUpstream  = "upstream"
+	ClassEcosystem = "ecosystem"
+	// ClassOther is work in orgs and repos no class lists.
+	ClassOther = "other"
+)
+
+// workClasses are the classes config may declare, in the order reports show them.
+var workClasses = []string{ClassInternal, ClassUpstream, ClassEcosystem}
+
+// WorkClass lists the orgs and repos (owner/name) whose work falls in a class.
+type WorkClass struct {
+	Orgs  []string `json:"orgs" yaml:"orgs"`
+	Repos []string `json:"repos" yaml:"repos"`
+}
+
+// Page name prefixes of the per-class dashboards and achievements.
+const (
+	classDashboardPage    = "user_dashboard_class_"
+	classAchievementsPage = "team_achievements_class_"
+)
+
+func (c *Config) validateClasses(l configLocator, errs *ConfigErrors) {
+	// Repeats within one class are left to checkLogins and checkRepos.
+	orgs := make(map[string]string)
+	repos := make(map[string]string)
+	for _, class := range sortedKeys(c.Classes) {
+		wc := c.Classes[class]
+		if !containsFold(workClasses, class) || class != strings.ToLower(class) {
+			errs.add(l, []interface{}{"classes", class}, "%q is not a work class; use one of %s", class, strings.Join(workClasses, ", "))
+			continue
+		}
+		checkLogins(l, errs, []interface{}{"classes", class, "orgs"}, wc.Orgs)
+		checkRepos(l, errs, []interface{}{"classes", class, "repos"}, wc.Repos)
+		for i, org := range wc.Orgs {
+			if other, ok := orgs[strings.ToLower(org)]; ok && other != class {
+				errs.add(l, []interface{}{"classes", class, "orgs", i}, "%q is already %s", org, other)
+			}
+			orgs[strings.ToLower(org)] = class
+		}
+		for i, repo := range wc.Repos {
+			if other, ok := repos[strings.ToLower(repo)]; ok && other != class {
+				errs.add(l, []interface{}{"classes", class, "repos", i}, "%q is already %s", repo, other)
+			}
+			repos[strings.ToLower(repo)] = class
+		}
+	}
+}
+
+// classOf is the class of work in owner/repo. A listed repo wins over its
+// org, so one repo can be classed apart from the rest of its org.
+func (d *Dataset) classOf(owner, repo string) string {
+	for _, class := range workClasses {
+		if containsFold(d.classes[class].Repos, owner+"/"+repo) {
+			return class
+		}
+	}
+	for _, class := range workClasses {
+		if containsFold(d.classes[class].Orgs, owner) {
+			return class
+		}
+	}
+	return ClassOther
+}
+
+// classNames are the configured classes followed by other, or nil without classes.
+func (d *Dataset) classNames() []string {
+	if len(d.classes) == 0 {
+		return nil
+	}
+	var names []string
+	for _, class := range workClasses {
+		if _, ok := d.classes[class]; ok {
+			names = append(names, class)
+		}
+	}
+	return append(names, ClassOther)
+}
+
+// byClass is a copy of d keeping only the work in class.
+func (d *Dataset) byClass(class string) *Dataset {
+	return d.keeping(func(issue Issue, _ []string) bool {
+		return d.classOf(issue.Owner, issue.Repo) == class
+	})
+}
+
+// classLinks link every page of a report split by class: "all" to the page
+// with every class, then one per class. current is the class being shown, or "".
+func (d *Dataset) classLinks(page, classPage, current string) []ClassLink {
+	names := d.classNames()
+	if names == nil {
+		return nil
+	}
+	links := []ClassLink{{Class: "all", Page: page, Current: current == ""}}
+	for _, class := range names {
+		links = append(links, ClassLink{Class: class, Page: classPage + class, Current: class == current})
+	}
+	return links
+}
+
+// classTitle names a class in page titles, e.g. "Upstream work".
+func classTitle(class string) string {
+	return strings.ToUpper(class[:1]) + class[1:] + " work"
+}
+
+func activityClasses(activities []Activity) []string {
+	classes := make([]string, len(activities))
+	for i, a := range activities {
+		classes[i] = a.Class
+	}
+	return classes
+}
+
+// bucketClasses counts the distinct issues and PRs on a user's dashboard per class.
+func (d *Dataset) bucketClasses(data *UserData) []ClassCount {
+	if d.classes == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var classes []string
+	for _, list := range [][]Issue{data.AssignedIssues, data.CreatedIssues, data.OpenPRs, data.ClosedPRs} {
+		for _, issue := range list {
+			if !seen[issue.URL] {
+				seen[issue.URL] = true
+				classes = append(classes, d.classOf(issue.Owner, issue.Repo))
+			}
+		}
+	}
+	return countClasses(classes)
+}
+
+// countClasses counts how often each class occurs, in the order classes are
+// shown. Empty classes are skipped, so it is nil for unclassified work.
+func countClasses(classes []string) []ClassCount {
+	counts := make(map[string]int)
+	for _, class := range classes {
+		if class != "" {
+			counts[class]++
+		}
+	}
+	var result []ClassCount
+	for _, class := range append(append([]string(nil), workClasses...), ClassOther) {
+		if counts[class] > 0 {
+			result = append(result, ClassCount{Class: class, Count: counts[class]})
+		}
+	}
+	return result
+}
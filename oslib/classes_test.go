@@ -0,0 +1,66 @@
+package oslib
+
+import "testing"
+
+func TestClassOf(t *testing.T) {
+	d := &Dataset{classes: map[string]WorkClass{
+		ClassInternal:  {Orgs: []string{"acme"}, Repos: []string{"kubernetes/acme-fork"}},
+		ClassUpstream:  {Orgs: []string{"kubernetes", "kubernetes-sigs"}, Repos: []string{"acme/upstream-mirror"}},
+		ClassEcosystem: {Repos: []string{"helm/helm"}},
+	}}
+	tests := []struct {
+		owner, repo string
+		want        string
+	}{
+		{"acme", "api", ClassInternal},
+		{"ACME", "api", ClassInternal},
+		{"kubernetes", "kubernetes", ClassUpstream},
+		{"kubernetes-sigs", "kind", ClassUpstream},
+		{"helm", "helm", ClassEcosystem},
+		{"helm", "charts", ClassOther},
+		// A listed repo wins over the class of its org.
+		{"kubernetes", "acme-fork", ClassInternal},
+		{"acme", "upstream-mirror", ClassUpstream},
+		{"octocat", "hello-world", ClassOther},
+	}
+	for _, tt := range tests {
+		if got := d.classOf(tt.owner, tt.repo); got != tt.want {
+			t.Errorf("classOf(%s/%s) = %q, want %q", tt.owner, tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestClassOfFirstClassWins(t *testing.T) {
+	// Validation rejects an org in two classes; should one slip through, the class order decides.
+	d := &Dataset{classes: map[string]WorkClass{
+		ClassEcosystem: {Orgs: []string{"shared"}},
+		ClassInternal:  {Orgs: []string{"shared"}},
+	}}
+	if got := d.classOf("shared", "x"); got != ClassInternal {
+		t.Errorf("classOf() = %q, want %q", got, ClassInternal)
+	}
+}
+
+func TestByClass(t *testing.T) {
+	d := &Dataset{
+		Users: []string{"octocat"},
+		UserData: map[string]*UserData{"octocat": {OpenPRs: []Issue{
+			{URL: "1", Owner: "acme", Repo: "api"},
+			{URL: "2", Owner: "kubernetes", Repo: "kubernetes"},
+			{URL: "3", Owner: "octocat", Repo: "hello-world"},
+		}}},
+		classes: map[string]WorkClass{ClassInternal: {Orgs: []string{"acme"}}, ClassUpstream: {Orgs: []string{"kubernetes"}}},
+	}
+	if got := d.classNames(); len(got) != 3 || got[0] != ClassInternal || got[1] != ClassUpstream || got[2] != ClassOther {
+		t.Errorf("classNames() = %v", got)
+	}
+	for class, want := range map[string]string{ClassInternal: "1", ClassUpstream: "2", ClassOther: "3"} {
+		prs := d.byClass(class).user("octocat").OpenPRs
+		if len(prs) != 1 || prs[0].URL != want {
+			t.Errorf("byClass(%s) open PRs = %+v, want only %s", class, prs, want)
+		}
+	}
+	if got := (&Dataset{}).classNames(); got != nil {
+		t.Errorf("classNames() without classes = %v, want nil", got)
+	}
+}
@@ -0,0 +1,95 @@
+package oslib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Comment is a user's comment on an issue or PR thread.
+type Comment struct {
+	Issue Issue `json:"issue"`
+	// URL links to the comment itself.
+	URL       string `json:"html_url"`
+	CreatedAt string `json:"created_at"`
+}
+
+// day is the UTC date the comment was made on, e.g. "2025-07-01".
+func (c Comment) day() string {
+	return c.CreatedAt[:min(len(c.CreatedAt), len(dateLayout))]
+}
+
+func commentedQuery(username string) SearchQuery {
+	oneYearAgo := time.Now().AddDate(-1, 0, 0)
+	return NewSearchQuery().Commenter(username).Updated(Since(oneYearAgo))
+}
+
+func fetchComments(ctx context.Context, q SearchQuery, username, token string) ([]Comment, error) {
+	issues, err := searchIssues(ctx, q, token)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(-1, 0, 0).UTC().Format(time.RFC3339)
+	var comments []Comment
+	for _, issue := range issues {
+		threadComments, err := fetchIssueComments(ctx, issue, since, token)
+		if err != nil {
+			return nil, fmt.Errorf("fetching comments of %s: %w", issue.URL, err)
+		}
+		for _, c := range threadComments {
+			if !strings.EqualFold(c.User.Login, username) || c.CreatedAt < since {
+				continue
+			}
+			upsertComment(&comments, Comment{Issue: issue, URL: c.URL, CreatedAt: c.CreatedAt})
+		}
+	}
+	return comments, nil
+}
+
+type issueComment struct {
+	User      webhookLogin `json:"user"`
+	URL       string       `json:"html_url"`
+	Body      string       `json:"body"`
+	CreatedAt string       `json:"created_at"`
+}
+
+// fetchIssueComments lists a thread's comments, only those updated since a timestamp when it is set.
+func fetchIssueComments(ctx context.Context, issue Issue, since, token string) ([]issueComment, error) {
+	var all []issueComment
+	for page := 1; ; page++ {
+		query := url.Values{"per_page": {"100"}, "page": {fmt.Sprint(page)}}
+		if since != "" {
+			query.Set("since", since)
+		}
+		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/comments?%s", url.PathEscape(issue.Owner), url.PathEscape(issue.Repo), issue.Number, query.Encode())
+		var comments []issueComment
+		if err := getGitHubJSON(ctx, apiURL, token, &comments); err != nil {
+			return nil, err
+		}
+		all = append(all, comments...)
+		if len(comments) < 100 {
+			return all, nil
+		}
+	}
+}
+
+// upsertComment adds c unless the thread already has a comment that day, so
+// a back-and-forth counts once. The earliest comment of the day is kept.
+func upsertComment(list *[]Comment, c Comment) {
+	for i, existing := range *list {
+		if existing.Issue.URL == c.Issue.URL && existing.day() == c.day() {
+			if c.CreatedAt < existing.CreatedAt {
+				(*list)[i] = c
+			}
+			return
+		}
+	}
+	*list = append(*list, c)
+	sort.SliceStable(*list, func(i, j int) bool {
+		return (*list)[i].CreatedAt > (*list)[j].CreatedAt
+	})
+}
@@ -0,0 +1,56 @@
+package oslib
+
+import "testing"
+
+func TestUpsertComment(t *testing.T) {
+	thread := Issue{URL: "https://github.com/o/r/issues/1"}
+	other := Issue{URL: "https://github.com/o/r/issues/2"}
+	comment := func(issue Issue, id, at string) Comment {
+		return Comment{Issue: issue, URL: issue.URL + "#issuecomment-" + id, CreatedAt: at}
+	}
+	tests := []struct {
+		name     string
+		comments []Comment
+		want     []string
+	}{
+		{"one comment", []Comment{comment(thread, "1", "2026-10-01T10:00:00Z")}, []string{"2026-10-01T10:00:00Z"}},
+		{"same thread and day", []Comment{
+			comment(thread, "2", "2026-10-01T15:00:00Z"),
+			comment(thread, "1", "2026-10-01T10:00:00Z"),
+		}, []string{"2026-10-01T10:00:00Z"}},
+		{"same thread on different days", []Comment{
+			comment(thread, "1", "2026-10-01T23:59:00Z"),
+			comment(thread, "2", "2026-10-02T00:01:00Z"),
+		}, []string{"2026-10-02T00:01:00Z", "2026-10-01T23:59:00Z"}},
+		{"different threads the same day", []Comment{
+			comment(thread, "1", "2026-10-01T10:00:00Z"),
+			comment(other, "2", "2026-10-01T11:00:00Z"),
+		}, []string{"2026-10-01T11:00:00Z", "2026-10-01T10:00:00Z"}},
+		// An edit keeps the comment's URL and creation time, so seeing it again changes nothing.
+		{"edited comment", []Comment{
+			comment(thread, "1", "2026-10-01T10:00:00Z"),
+			comment(thread, "1", "2026-10-01T10:00:00Z"),
+		}, []string{"2026-10-01T10:00:00Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var list []Comment
+			for _, c := range tt.comments {
+				upsertComment(&list, c)
+			}
+			var got []string
+			for _, c := range list {
+				got = append(got, c.CreatedAt)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("upsertComment() kept %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("upsertComment() kept %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
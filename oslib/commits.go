@@ -0,0 +1,126 @@
+package oslib
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Commit is a commit a user authored, or co-authored through a Co-authored-by trailer.
+type Commit struct {
+	SHA   string `json:"sha"`
+	URL   string `json:"html_url"`
+	Repo  string `json:"repo"`
+	Owner string `json:"owner"`
+	// Message is the first line of the commit message.
+	Message string `json:"message"`
+	// Date is when the commit was committed, which for a cherry-pick is when it was picked.
+	Date       string `json:"date"`
+	CoAuthored bool   `json:"co_authored,omitempty"`
+}
+
+// ShortSHA is the abbreviated commit hash.
+func (c Commit) ShortSHA() string {
+	return c.SHA[:min(len(c.SHA), 7)]
+}
+
+// repoIssue stands in for the commit where filters look at repos.
+func (c Commit) repoIssue() Issue {
+	return Issue{URL: c.URL, Repo: c.Repo, Owner: c.Owner}
+}
+
+// searchCommit is one item of a commit search.
+type searchCommit struct {
+	SHA    string `json:"sha"`
+	URL    string `json:"html_url"`
+	Commit struct {
+		Message   string `json:"message"`
+		Committer struct {
+			Date string `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+	Repository struct {
+		Name  string       `json:"name"`
+		Owner webhookLogin `json:"owner"`
+	} `json:"repository"`
+}
+
+func authoredCommitsQuery(username string) SearchQuery {
+	oneYearAgo := time.Now().AddDate(-1, 0, 0)
+	return NewSearchQuery().Author(username).With("merge", "false").CommitterDate(Since(oneYearAgo))
+}
+
+// coAuthoredCommitsQuery finds candidate commits naming username; their trailers decide which count.
+func coAuthoredCommitsQuery(username string) SearchQuery {
+	oneYearAgo := time.Now().AddDate(-1, 0, 0)
+	return NewSearchQuery().Text("co-authored-by").Text(username).Without("author", username).CommitterDate(Since(oneYearAgo))
+}
+
+func fetchCommits(ctx context.Context, authored, coAuthored SearchQuery, username, token string) ([]Commit, error) {
+	var commits []Commit
+	for i, q := range []SearchQuery{authored, coAuthored} {
+		co := i == 1
+		items, err := searchPages[searchCommit](ctx, q.CommitsURL, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			c := Commit{
+				SHA:     item.SHA,
+				URL:     item.URL,
+				Repo:    item.Repository.Name,
+				Owner:   item.Repository.Owner.Login,
+				Message: strings.TrimSpace(strings.SplitN(item.Commit.Message, "\n", 2)[0]),
+				Date:    item.Commit.Committer.Date,
+			}
+			if co {
+				if !coAuthoredBy(item.Commit.Message, username) {
+					continue
+				}
+				c.CoAuthored = true
+			}
+			upsertCommit(&commits, c)
+		}
+	}
+	return commits, nil
+}
+
+var coAuthorTrailer = regexp.MustCompile(`(?im)^co-authored-by:\s*(.*?)\s*<([^>]*)>\s*$`)
+
+// coAuthoredBy reports whether a commit message has a Co-authored-by trailer
+// for login, either by its GitHub noreply address or by a name equal to the login.
+// Trailers follow the subject line, so the subject itself is not read.
+func coAuthoredBy(message, login string) bool {
+	_, body, _ := strings.Cut(message, "\n")
+	for _, m := range coAuthorTrailer.FindAllStringSubmatch(body, -1) {
+		name, email := m[1], strings.ToLower(m[2])
+		local, ok := strings.CutSuffix(email, "@users.noreply.github.com")
+		if ok {
+			if _, after, found := strings.Cut(local, "+"); found {
+				local = after
+			}
+			if strings.EqualFold(local, login) {
+				return true
+			}
+		}
+		if strings.EqualFold(name, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// upsertCommit adds c unless the same commit is already listed, keeping the list newest first.
+func upsertCommit(list *[]Commit, c Commit) {
+	for _, existing := range *list {
+		if existing.SHA == c.SHA && strings.EqualFold(existing.Owner, c.Owner) && strings.EqualFold(existing.Repo, c.Repo) {
+			return
+		}
+	}
+	*list = append(*list, c)
+	sort.SliceStable(*list, func(i, j int) bool {
+		return (*list)[i].Date > (*list)[j].Date
+	})
+}
@@ -0,0 +1,33 @@
+package oslib
+
+import "testing"
+
+func TestCoAuthoredBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		login   string
+		want    bool
+	}{
+		{"noreply address", "Fix it\n\nCo-authored-by: Octo Cat <12345+octocat@users.noreply.github.com>", "octocat", true},
+		{"legacy noreply address", "Fix it\n\nCo-authored-by: Octo Cat <octocat@users.noreply.github.com>", "octocat", true},
+		{"login as the name", "Fix it\n\nCo-authored-by: octocat <octo@example.com>", "octocat", true},
+		{"case-insensitive trailer and login", "Fix it\n\nco-AUTHORED-by: OctoCat <octo@example.com>", "octocat", true},
+		{"one of several trailers", "Fix it\n\nCo-authored-by: alice <a@example.com>\nCo-authored-by: Octo <1+octocat@users.noreply.github.com>", "octocat", true},
+		{"subject line only", "Co-authored-by: octocat <octo@example.com>", "octocat", false},
+		{"subject line with a body", "Co-authored-by: octocat <octo@example.com>\n\nSomething else", "octocat", false},
+		{"other address", "Fix it\n\nCo-authored-by: Octo Cat <octocat@example.com>", "octocat", false},
+		{"login prefix of the name", "Fix it\n\nCo-authored-by: octocat2 <o@example.com>", "octocat", false},
+		{"login prefix of the noreply login", "Fix it\n\nCo-authored-by: Someone <7+octocat2@users.noreply.github.com>", "octocat", false},
+		{"longer login than the name", "Fix it\n\nCo-authored-by: octo <o@example.com>", "octocat", false},
+		{"mentioned outside a trailer", "Fix it\n\nThanks to octocat, see Co-authored-by: octocat <o@example.com> below", "octocat", false},
+		{"no trailer", "Fix it\n\nSigned-off-by: octocat <o@example.com>", "octocat", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coAuthoredBy(tt.message, tt.login); got != tt.want {
+				t.Errorf("coAuthoredBy(%q, %q) = %v, want %v", tt.message, tt.login, got, tt.want)
+			}
+		})
+	}
+}
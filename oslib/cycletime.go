@@ -0,0 +1,212 @@
+package oslib
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PRCycle is how one of a user's PRs went through review.
+type PRCycle struct {
+	PR Issue `json:"pr"`
+	// FirstReviewAt is when someone other than the author first reviewed it, if anyone has.
+	FirstReviewAt string `json:"first_review_at,omitempty"`
+	// Rounds counts the batches of reviews, each one following new commits.
+	Rounds int `json:"rounds"`
+}
+
+// firstReviewStates are the reviews that end the wait for a first review.
+var firstReviewStates = append([]string{"DISMISSED"}, reviewStates...)
+
+// FetchPRCycle looks up the reviews and timeline of a PR authored by author.
+func FetchPRCycle(ctx context.Context, pr Issue, author, token string) (PRCycle, error) {
+	cycle := PRCycle{PR: pr}
+	reviews, err := fetchPRReviews(ctx, pr, token)
+	if err != nil {
+		return cycle, err
+	}
+	for _, r := range reviews {
+		if strings.EqualFold(r.User.Login, author) || !containsFold(firstReviewStates, r.State) {
+			continue
+		}
+		if cycle.FirstReviewAt == "" || r.SubmittedAt < cycle.FirstReviewAt {
+			cycle.FirstReviewAt = r.SubmittedAt
+		}
+	}
+	if cycle.FirstReviewAt == "" {
+		return cycle, nil
+	}
+
+	events, err := fetchPRTimeline(ctx, pr, token)
+	if err != nil {
+		return cycle, err
+	}
+	pushed := false
+	for _, e := range events {
+		switch e.Event {
+		case "committed":
+			pushed = true
+		case "reviewed":
+			if strings.EqualFold(e.User.Login, author) || strings.EqualFold(e.State, "pending") {
+				continue
+			}
+			if cycle.Rounds == 0 || pushed {
+				cycle.Rounds++
+				pushed = false
+			}
+		}
+	}
+	return cycle, nil
+}
+
+type timelineEvent struct {
+	Event string       `json:"event"`
+	User  webhookLogin `json:"user"`
+	State string       `json:"state"`
+}
+
+func fetchPRTimeline(ctx context.Context, pr Issue, token string) ([]timelineEvent, error) {
+	var all []timelineEvent
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/timeline?per_page=100&page=%d", url.PathEscape(pr.Owner), url.PathEscape(pr.Repo), pr.Number, page)
+		var events []timelineEvent
+		if err := getGitHubJSON(ctx, apiURL, token, &events); err != nil {
+			return nil, err
+		}
+		all = append(all, events...)
+		if len(events) < 100 {
+			return all, nil
+		}
+	}
+}
+
+// fetchPRCycles looks up the review history of every PR in prs.
+func fetchPRCycles(ctx context.Context, prs []Issue, author, token string) ([]PRCycle, error) {
+	var cycles []PRCycle
+	for _, pr := range prs {
+		cycle, err := FetchPRCycle(ctx, pr, author, token)
+		if err != nil {
+			return nil, fmt.Errorf("fetching review history of %s: %w", pr.URL, err)
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles, nil
+}
+
+// hoursBetween is the time from one RFC 3339 timestamp to another, in hours.
+func hoursBetween(from, to string) (float64, bool) {
+	start, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return 0, false
+	}
+	end, err := time.Parse(time.RFC3339, to)
+	if err != nil {
+		return 0, false
+	}
+	return end.Sub(start).Hours(), true
+}
+
+func newCycleRow(user, name string, c PRCycle) PRCycleRow {
+	row := PRCycleRow{
+		User:      user,
+		Name:      name,
+		Title:     c.PR.Title,
+		URL:       c.PR.URL,
+		Repo:      c.PR.FullRepo(),
+		CreatedAt: c.PR.CreatedAt,
+		Rounds:    c.Rounds,
+	}
+	if c.FirstReviewAt != "" {
+		row.FirstReviewHours, row.Reviewed = hoursBetween(c.PR.CreatedAt, c.FirstReviewAt)
+	}
+	if c.PR.Merged() {
+		row.MergeHours, row.Merged = hoursBetween(c.PR.CreatedAt, c.PR.PullRequest.MergedAt)
+	}
+	return row
+}
+
+// newCycleGroup summarises rows. Review rounds only count for reviewed PRs.
+func newCycleGroup(name string, rows []PRCycleRow) CycleGroup {
+	var firstReview, merge, rounds []float64
+	for _, r := range rows {
+		if r.Reviewed {
+			firstReview = append(firstReview, r.FirstReviewHours)
+			rounds = append(rounds, float64(r.Rounds))
+		}
+		if r.Merged {
+			merge = append(merge, r.MergeHours)
+		}
+	}
+	return CycleGroup{
+		Name:        name,
+		PRs:         len(rows),
+		FirstReview: newCycleStat(firstReview),
+		Merge:       newCycleStat(merge),
+		Rounds:      newCycleStat(rounds),
+	}
+}
+
+func newCycleStat(values []float64) CycleStat {
+	if len(values) == 0 {
+		return CycleStat{}
+	}
+	sort.Float64s(values)
+	return CycleStat{Count: len(values), Median: percentile(values, 50), P90: percentile(values, 90)}
+}
+
+// percentile is the nearest-rank percentile p of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// CycleTimeReport groups every fetched PR's review and merge times by user, repo and month.
+func (d *Dataset) CycleTimeReport() CycleTimeReport {
+	var report CycleTimeReport
+	users := append([]string(nil), d.Users...)
+	d.sortByName(users)
+	byUser := make(map[string][]PRCycleRow)
+	byRepo := make(map[string][]PRCycleRow)
+	byMonth := make(map[string][]PRCycleRow)
+	seen := make(map[string]bool)
+	for _, user := range users {
+		name := d.person(user).DisplayName()
+		for _, c := range d.user(user).PRCycles {
+			if seen[c.PR.URL] {
+				continue
+			}
+			seen[c.PR.URL] = true
+			row := newCycleRow(user, name, c)
+			report.PRs = append(report.PRs, row)
+			byUser[user] = append(byUser[user], row)
+			byRepo[row.Repo] = append(byRepo[row.Repo], row)
+			month := row.CreatedAt[:min(len(row.CreatedAt), len("2006-01"))]
+			byMonth[month] = append(byMonth[month], row)
+		}
+	}
+	sort.SliceStable(report.PRs, func(i, j int) bool {
+		return report.PRs[i].CreatedAt > report.PRs[j].CreatedAt
+	})
+
+	report.All = newCycleGroup("All PRs", report.PRs)
+	for _, user := range users {
+		if rows, ok := byUser[user]; ok {
+			report.Users = append(report.Users, newCycleGroup(d.person(user).DisplayName(), rows))
+		}
+	}
+	for _, repo := range sortedKeys(byRepo) {
+		report.Repos = append(report.Repos, newCycleGroup(repo, byRepo[repo]))
+	}
+	sort.SliceStable(report.Repos, func(i, j int) bool {
+		return report.Repos[i].PRs > report.Repos[j].PRs
+	})
+	months := sortedKeys(byMonth)
+	for i := len(months) - 1; i >= 0; i-- {
+		report.Months = append(report.Months, newCycleGroup(months[i], byMonth[months[i]]))
+	}
+	return report
+}
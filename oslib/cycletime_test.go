@@ -0,0 +1,37 @@
+package oslib
+
+import "testing"
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{"single value", []float64{7}, 50, 7},
+		{"median of odd count", []float64{1, 2, 3, 4, 5}, 50, 3},
+		{"median of even count is the lower middle", []float64{1, 2, 3, 4}, 50, 2},
+		{"p90 of ten", []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 90, 9},
+		{"p90 of eleven rounds up", []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, 90, 10},
+		{"p0 is the smallest", []float64{3, 4}, 0, 3},
+		{"p100 is the largest", []float64{3, 4}, 100, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("percentile(%v, %v) = %v, want %v", tt.sorted, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCycleStat(t *testing.T) {
+	if got := newCycleStat(nil); got != (CycleStat{}) {
+		t.Errorf("newCycleStat(nil) = %+v, want zero", got)
+	}
+	got := newCycleStat([]float64{5, 1, 3})
+	if got.Count != 3 || got.Median != 3 || got.P90 != 5 {
+		t.Errorf("newCycleStat() = %+v, want 3 values with median 3 and p90 5", got)
+	}
+}
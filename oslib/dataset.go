@@ -0,0 +1,705 @@
+package oslib
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// Need is a set of data a report is rendered from, so a run that renders
+// several reports fetches each piece only once.
+type Need uint
+
+const (
+	NeedAssignedIssues Need = 1 << iota
+	NeedCreatedIssues
+	NeedClosedIssues
+	NeedOpenPRs
+	NeedClosedPRs
+	NeedKubernetesPRs
+	NeedLabelIssues
+	// NeedUserList only resolves who is tracked, including members of GitHub teams.
+	NeedUserList
+	NeedReviews
+	NeedComments
+	NeedCommits
+	// NeedPRCycles looks up the review history of the open and closed PRs.
+	NeedPRCycles
+	// NeedLastReplies looks up when others last replied on the open PRs and assigned issues.
+	NeedLastReplies
+	// NeedPRStatus looks up the reviews, checks and mergeability of the open PRs.
+	NeedPRStatus
+	// NeedWaiting finds the review requests, mentions and assigned PRs waiting on each user.
+	NeedWaiting
+	// NeedPRSizes looks up the lines changed by the open and closed PRs.
+	NeedPRSizes
+
+	needUserData = NeedAssignedIssues | NeedCreatedIssues | NeedClosedIssues | NeedOpenPRs | NeedClosedPRs | NeedKubernetesPRs | NeedReviews | NeedComments | NeedCommits | NeedPRCycles | NeedLastReplies | NeedPRStatus | NeedWaiting | NeedPRSizes
+)
+
+// Reports are named after the page kind they produce, in the order "all" renders them.
+var Reports = []string{PageDashboard, PageIssues, PageAchievements, PageKubernetes, PageTeams, PageUserChanges, PageCycleTime, PageStale, PageLeaderboard}
+
+var reportNeeds = map[string]Need{
+	PageDashboard:    NeedAssignedIssues | NeedCreatedIssues | NeedOpenPRs | NeedClosedPRs | NeedReviews | NeedWaiting,
+	PageIssues:       NeedLabelIssues,
+	PageAchievements: NeedCreatedIssues | NeedClosedIssues | NeedOpenPRs | NeedClosedPRs | NeedReviews | NeedComments | NeedCommits,
+	PageLeaderboard:  NeedCreatedIssues | NeedClosedIssues | NeedOpenPRs | NeedClosedPRs | NeedReviews | NeedComments | NeedCommits,
+	PageKubernetes:   NeedKubernetesPRs,
+	PageTeams:        NeedAssignedIssues | NeedCreatedIssues | NeedClosedIssues | NeedOpenPRs | NeedClosedPRs | NeedReviews | NeedComments | NeedCommits,
+	PageUserChanges:  NeedUserList,
+	PageCycleTime:    NeedOpenPRs | NeedClosedPRs | NeedPRCycles,
+	PageStale:        NeedAssignedIssues | NeedOpenPRs,
+}
+
+// NeedsFor is the union of the data needed by reports.
+func NeedsFor(reports []string) Need {
+	var need Need
+	for _, report := range reports {
+		need |= reportNeeds[report]
+	}
+	return need
+}
+
+// optedOut is the data that costs API calls per item and the config leaves off.
+func (c *Config) optedOut() Need {
+	var off Need
+	if !c.TrackReviews {
+		off |= NeedReviews
+	}
+	if !c.TrackComments {
+		off |= NeedComments
+	}
+	if !c.DashboardWaiting {
+		off |= NeedWaiting
+	}
+	if !c.CycleTime {
+		off |= NeedPRCycles
+	}
+	return off
+}
+
+// Dataset is everything fetched from GitHub in one run. Every report is rendered from it.
+type Dataset struct {
+	FetchedAt time.Time
+	Users     []string
+	Teams     []Team
+	// People holds the configured details of each user, keyed by main login.
+	People   map[string]User
+	UserData map[string]*UserData
+	Labels   []LabelIssues
+	// Repos holds what filters need to know about each repo, keyed by lower-cased owner/name.
+	Repos map[string]RepoInfo
+
+	// allUsers is set when Users is every configured user rather than a chosen few.
+	allUsers bool
+	// showCommits puts commits on the dashboards, and showWaiting what waits on each user.
+	showCommits bool
+	showWaiting bool
+	// trackReviews and trackComments are set when reviews and comments were fetched.
+	trackReviews  bool
+	trackComments bool
+	// cycleTime is set when the review history of PRs was fetched for the cycle time page.
+	cycleTime bool
+	stale     Staleness
+	// scoring weighs activity for the leaderboard; nil when it is disabled.
+	scoring *Scoring
+	// classes are the configured work classes, by name.
+	classes map[string]WorkClass
+}
+
+type UserData struct {
+	AssignedIssues []Issue
+	CreatedIssues  []Issue
+	ClosedIssues   []Issue
+	OpenPRs        []Issue
+	ClosedPRs      []Issue
+	KubernetesPRs  []Issue
+	// Reviews are the user's reviews of other people's PRs, newest first.
+	Reviews []Review
+	// Comments are the user's comments on issue and PR threads, one per thread and day.
+	Comments []Comment
+	// Commits are the user's authored and co-authored commits, newest first.
+	Commits []Commit
+	// PRCycles is the review history of OpenPRs and ClosedPRs.
+	PRCycles []PRCycle
+	// LastReplies maps the URLs of OpenPRs and AssignedIssues to when someone
+	// other than their author last commented or reviewed.
+	LastReplies map[string]string
+	// PRStatus maps the URLs of OpenPRs to their review, CI and merge state.
+	PRStatus map[string]PRStatus
+	// Waiting holds the open review requests, mentions and assigned PRs, whoever acted last on them.
+	Waiting []WaitingItem
+	// PRSizes maps the URLs of OpenPRs and ClosedPRs to the lines they change.
+	PRSizes map[string]int
+}
+
+// LabelIssues holds the open issues carrying one label, per org.
+type LabelIssues struct {
+	Label Label
+	ByOrg map[string][]Issue
+	// Claims maps the URLs of issues someone asked to work on to that comment,
+	// when the label detects claims.
+	Claims map[string]Claim
+}
+
+// All is every org's issues merged, newest first.
+func (l LabelIssues) All() []Issue {
+	var all []Issue
+	for _, org := range sortedKeys(l.ByOrg) {
+		all = append(all, l.ByOrg[org]...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].CreatedAt > all[j].CreatedAt
+	})
+	return all
+}
+
+// Free is every issue nobody claimed, newest first.
+func (l LabelIssues) Free() []Issue {
+	return l.unclaimed(l.All())
+}
+
+func (l LabelIssues) unclaimed(issues []Issue) []Issue {
+	var free []Issue
+	for _, issue := range issues {
+		if _, ok := l.Claims[issue.URL]; !ok {
+			free = append(free, issue)
+		}
+	}
+	return free
+}
+
+// Taken is every claimed issue, newest first.
+func (l LabelIssues) Taken() []TakenIssue {
+	var taken []TakenIssue
+	for _, issue := range l.All() {
+		if claim, ok := l.Claims[issue.URL]; ok {
+			taken = append(taken, TakenIssue{Issue: issue, Claim: claim})
+		}
+	}
+	return taken
+}
+
+func (l LabelIssues) has(url string) bool {
+	for _, issues := range l.ByOrg {
+		for _, issue := range issues {
+			if issue.URL == url {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// summary is the label's line on the labels index.
+func (l LabelIssues) summary() LabelSummary {
+	return LabelSummary{Label: l.Label.Name, Title: l.Label.DisplayName(), Page: l.Label.Slug(), Count: len(l.Free()), Taken: len(l.Taken())}
+}
+
+// FetchDataset fetches what reports need, pausing delay between users to stay under the search rate limit.
+// Cancelling ctx stops it, including during those pauses.
+// config must not be nil. A nil users means every user in config, looking up the members of its GitHub teams.
+func FetchDataset(ctx context.Context, config *Config, users []string, token string, delay time.Duration, reports []string) (*Dataset, error) {
+	need := NeedsFor(reports) &^ config.optedOut()
+	scope := config.searchFilter(reports)
+	d := &Dataset{
+		FetchedAt:     time.Now(),
+		Users:         users,
+		UserData:      make(map[string]*UserData),
+		stale:         config.staleness(),
+		scoring:       config.scoring(),
+		showCommits:   config.DashboardCommits,
+		showWaiting:   need&NeedWaiting != 0,
+		trackReviews:  need&NeedReviews != 0,
+		trackComments: need&NeedComments != 0,
+		cycleTime:     need&NeedPRCycles != 0,
+		classes:       config.Classes,
+	}
+	if config.DashboardCommits && containsFold(reports, PageDashboard) {
+		need |= NeedCommits
+	}
+	if config.DashboardPRStatus && containsFold(reports, PageDashboard) {
+		need |= NeedPRStatus
+	}
+	if d.scoring != nil && len(d.scoring.Sizes) > 0 && containsFold(reports, PageLeaderboard) {
+		need |= NeedPRSizes
+	}
+	if d.stale.DaysSinceComment > 0 && (containsFold(reports, PageDashboard) || containsFold(reports, PageStale)) {
+		need |= NeedLastReplies
+	}
+
+	if users == nil && need&(needUserData|NeedUserList) != 0 {
+		if config.syncsGitHubTeams() {
+			resolved, err := ResolveTeams(ctx, config, token)
+			if err != nil {
+				return nil, err
+			}
+			config = resolved
+		}
+		d.Users, d.allUsers = config.Logins(), true
+	}
+	d.Teams = config.Teams
+
+	// Users chosen by an alias are reported under their main login.
+	d.Users = append([]string(nil), d.Users...)
+	d.People = make(map[string]User)
+	for i, login := range d.Users {
+		if u, ok := config.person(login); ok {
+			d.Users[i] = u.Login
+			d.People[u.Login] = u
+		}
+	}
+
+	if need&needUserData != 0 {
+		fetched := 0
+		for _, user := range d.Users {
+			data := &UserData{}
+			for _, login := range d.person(user).Logins() {
+				if fetched > 0 && delay > 0 {
+					log.Printf("Sleeping for %s to avoid rate-limiting", delay)
+					if err := sleep(ctx, delay); err != nil {
+						return nil, err
+					}
+				}
+				loginData, err := fetchUserData(ctx, login, token, need, scope)
+				if err != nil {
+					return nil, fmt.Errorf("fetching %s: %w", login, err)
+				}
+				data.merge(loginData)
+				fetched++
+			}
+			d.UserData[user] = data
+		}
+	}
+
+	if need&NeedLabelIssues != 0 {
+		for _, label := range config.Labels {
+			l := LabelIssues{Label: label, ByOrg: make(map[string][]Issue)}
+			for _, org := range config.Orgs {
+				issues, err := FetchIssues(ctx, org, token, label)
+				if err != nil {
+					return nil, fmt.Errorf("fetching %q issues in %s: %w", label.Name, org, err)
+				}
+				l.ByOrg[org] = issues
+				if label.DetectClaims {
+					claims, err := fetchClaims(ctx, issues, token)
+					if err != nil {
+						return nil, fmt.Errorf("finding claimed %q issues in %s: %w", label.Name, org, err)
+					}
+					if l.Claims == nil {
+						l.Claims = make(map[string]Claim)
+					}
+					for url, claim := range claims {
+						l.Claims[url] = claim
+					}
+				}
+			}
+			d.Labels = append(d.Labels, l)
+		}
+	}
+
+	if config.needsRepoInfo(reports) {
+		if err := d.fetchRepoInfo(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// fetchUserData runs the searches need asks for, narrowed by scope when set.
+func fetchUserData(ctx context.Context, user, token string, need Need, scope *RepoFilter) (*UserData, error) {
+	data := &UserData{}
+	var err error
+	fetch := func(n Need, dst *[]Issue, q SearchQuery) {
+		if err != nil || need&n == 0 {
+			return
+		}
+		*dst, err = searchIssues(ctx, scope.qualify(q, user), token)
+	}
+	fetch(NeedAssignedIssues, &data.AssignedIssues, assignedIssuesQuery(user))
+	fetch(NeedCreatedIssues, &data.CreatedIssues, createdIssuesQuery(user))
+	fetch(NeedClosedIssues, &data.ClosedIssues, closedIssuesQuery(user))
+	fetch(NeedOpenPRs, &data.OpenPRs, openPRsQuery(user))
+	fetch(NeedClosedPRs, &data.ClosedPRs, closedPRsQuery(user))
+	if err == nil && need&NeedPRStatus != 0 {
+		data.PRStatus, err = fetchPRStatuses(ctx, data.OpenPRs, token)
+	}
+	if err == nil && need&NeedWaiting != 0 {
+		data.Waiting, err = fetchWaiting(ctx, map[string]SearchQuery{
+			WaitReviewRequested: scope.qualify(reviewRequestedQuery(user), user),
+			WaitMentioned:       scope.qualify(mentionsQuery(user), user),
+			WaitAssigned:        scope.qualify(assignedPRsQuery(user), user),
+		}, token)
+	}
+	if err == nil && need&NeedLastReplies != 0 {
+		data.LastReplies, err = fetchLastReplies(ctx, append(append([]Issue(nil), data.OpenPRs...), data.AssignedIssues...), token)
+	}
+	if err == nil && need&NeedPRSizes != 0 {
+		data.PRSizes, err = fetchPRSizes(ctx, append(append([]Issue(nil), data.OpenPRs...), data.ClosedPRs...), token)
+	}
+	if err == nil && need&NeedPRCycles != 0 {
+		data.PRCycles, err = fetchPRCycles(ctx, append(append([]Issue(nil), data.OpenPRs...), data.ClosedPRs...), user, token)
+	}
+	if err == nil && need&NeedReviews != 0 {
+		data.Reviews, err = fetchReviews(ctx, scope.qualify(reviewedPRsQuery(user), user), user, token)
+	}
+	if err == nil && need&NeedComments != 0 {
+		data.Comments, err = fetchComments(ctx, scope.qualify(commentedQuery(user), user), user, token)
+	}
+	if err == nil && need&NeedCommits != 0 {
+		data.Commits, err = fetchCommits(ctx, scope.qualifyCommits(authoredCommitsQuery(user), user), scope.qualifyCommits(coAuthoredCommitsQuery(user), user), user, token)
+	}
+	if err == nil && need&NeedKubernetesPRs != 0 {
+		// These searches are already scoped to the Kubernetes orgs; filters apply afterwards.
+		var prs map[string][]Issue
+		prs, err = FetchKubernetesPRs(ctx, []string{user}, token)
+		data.KubernetesPRs = prs[user]
+	}
+	return data, err
+}
+
+// merge adds the issues and PRs fetched for another login of the same person.
+func (data *UserData) merge(other *UserData) {
+	mergeIssues := func(dst *[]Issue, src []Issue) {
+		if *dst == nil {
+			*dst = src
+			return
+		}
+		for _, issue := range src {
+			upsertIssue(dst, issue)
+		}
+	}
+	mergeIssues(&data.AssignedIssues, other.AssignedIssues)
+	mergeIssues(&data.CreatedIssues, other.CreatedIssues)
+	mergeIssues(&data.ClosedIssues, other.ClosedIssues)
+	mergeIssues(&data.OpenPRs, other.OpenPRs)
+	mergeIssues(&data.ClosedPRs, other.ClosedPRs)
+	mergeIssues(&data.KubernetesPRs, other.KubernetesPRs)
+	for _, r := range other.Reviews {
+		upsertReview(&data.Reviews, r)
+	}
+	for _, c := range other.Comments {
+		upsertComment(&data.Comments, c)
+	}
+	for _, c := range other.Commits {
+		upsertCommit(&data.Commits, c)
+	}
+	data.PRCycles = append(data.PRCycles, other.PRCycles...)
+	if other.LastReplies != nil && data.LastReplies == nil {
+		data.LastReplies = make(map[string]string)
+	}
+	for url, at := range other.LastReplies {
+		data.LastReplies[url] = max(data.LastReplies[url], at)
+	}
+	if other.PRStatus != nil && data.PRStatus == nil {
+		data.PRStatus = make(map[string]PRStatus)
+	}
+	for url, status := range other.PRStatus {
+		data.PRStatus[url] = status
+	}
+	if other.PRSizes != nil && data.PRSizes == nil {
+		data.PRSizes = make(map[string]int)
+	}
+	for url, lines := range other.PRSizes {
+		data.PRSizes[url] = lines
+	}
+	for _, w := range other.Waiting {
+		upsertWaiting(&data.Waiting, w)
+	}
+}
+
+func (d *Dataset) user(login string) *UserData {
+	if data, ok := d.UserData[login]; ok {
+		return data
+	}
+	return &UserData{}
+}
+
+// person is the configured user behind login, which may be an alias, or a bare user when none is configured.
+func (d *Dataset) person(login string) User {
+	if u, ok := d.People[login]; ok {
+		return u
+	}
+	for _, u := range d.People {
+		if u.Has(login) {
+			return u
+		}
+	}
+	return User{Login: login}
+}
+
+// displayNames maps logins to the names reports show for them.
+func (d *Dataset) displayNames(logins []string) []string {
+	var names []string
+	for _, login := range logins {
+		names = append(names, d.person(login).DisplayName())
+	}
+	return names
+}
+
+// sortByName orders logins by the names reports show for them.
+func (d *Dataset) sortByName(logins []string) {
+	sort.SliceStable(logins, func(i, j int) bool {
+		return d.person(logins[i]).DisplayName() < d.person(logins[j]).DisplayName()
+	})
+}
+
+// inTeam reports whether any of user's logins is a lead or member of team.
+func (d *Dataset) inTeam(team Team, user string) bool {
+	for _, login := range d.person(user).Logins() {
+		if containsFold(team.AllMembers(), login) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dataset) leads(team Team, user string) bool {
+	for _, login := range d.person(user).Logins() {
+		if team.IsLead(login) {
+			return true
+		}
+	}
+	return false
+}
+
+// Page names of the per-team reports are these prefixes followed by the team's slug.
+const (
+	teamDashboardPage    = "user_dashboard_"
+	teamAchievementsPage = "team_achievements_"
+)
+
+// noTeam names the section of users who are in no configured team.
+const noTeam = "Not in a team"
+
+// userGroup is a team narrowed to the fetched users. A nil Users means everyone.
+type userGroup struct {
+	Team  Team
+	Users []string
+}
+
+// page is the name of the group's own page, or "" when it is not a configured team.
+func (g userGroup) page(prefix string) string {
+	if len(g.Team.AllMembers()) == 0 {
+		return ""
+	}
+	return prefix + g.Team.Slug()
+}
+
+// groups splits the fetched users by team, followed by those in no team.
+// Without teams it is a single group of every user.
+func (d *Dataset) groups() []userGroup {
+	users := append([]string(nil), d.Users...)
+	d.sortByName(users)
+	if len(d.Teams) == 0 {
+		return []userGroup{{Users: users}}
+	}
+
+	groups := d.teamGroups()
+	var others []string
+	for _, user := range users {
+		found := false
+		for _, g := range groups {
+			found = found || containsFold(g.Users, user)
+		}
+		if !found {
+			others = append(others, user)
+		}
+	}
+	if others != nil {
+		groups = append(groups, userGroup{Team: Team{Name: noTeam}, Users: others})
+	}
+	return groups
+}
+
+// teamGroups is every configured team with at least one fetched user, in config order.
+func (d *Dataset) teamGroups() []userGroup {
+	var groups []userGroup
+	for _, team := range d.Teams {
+		g := userGroup{Team: team}
+		for _, user := range d.Users {
+			if d.inTeam(team, user) {
+				g.Users = append(g.Users, user)
+			}
+		}
+		if g.Users != nil {
+			d.sortByName(g.Users)
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+func (d *Dataset) DashboardReport() DashboardReport {
+	report := d.dashboardReport("GitHub Dashboard", d.groups(), true)
+	report.Classes = d.classLinks("user_dashboard", classDashboardPage, "")
+	return report
+}
+
+// classDashboardReport is the dashboard of the work in one class.
+func (d *Dataset) classDashboardReport(class string) DashboardReport {
+	c := d.byClass(class)
+	report := c.dashboardReport("GitHub Dashboard: "+classTitle(class), c.groups(), false)
+	report.Classes = d.classLinks("user_dashboard", classDashboardPage, class)
+	return report
+}
+
+// teamDashboardReport is the dashboard of one team's users.
+func (d *Dataset) teamDashboardReport(g userGroup) DashboardReport {
+	return d.dashboardReport("GitHub Dashboard: "+g.Team.Name, []userGroup{g}, false)
+}
+
+func (d *Dataset) dashboardReport(title string, groups []userGroup, linkTeams bool) DashboardReport {
+	report := DashboardReport{Title: title, ShowCommits: d.showCommits, ShowReviews: d.trackReviews, ShowWaiting: d.showWaiting, Stale: d.stale}
+	for _, g := range groups {
+		section := DashboardSection{Team: g.Team.Name, Leads: d.displayNames(g.Team.Leads)}
+		if linkTeams {
+			section.Page = g.page(teamDashboardPage)
+		}
+		for _, user := range g.Users {
+			data := d.user(user)
+			person := d.person(user)
+			var stale map[string]string
+			items := d.staleItems(user)
+			for _, item := range items {
+				if stale == nil {
+					stale = make(map[string]string)
+				}
+				stale[item.Issue.URL] = item.Reason
+			}
+			section.Users = append(section.Users, UserBuckets{
+				User:           user,
+				Name:           person.DisplayName(),
+				Avatar:         person.Avatar,
+				Lead:           d.leads(g.Team, user),
+				Classes:        d.bucketClasses(data),
+				AssignedIssues: data.AssignedIssues,
+				CreatedIssues:  data.CreatedIssues,
+				OpenPRs:        data.OpenPRs,
+				ClosedPRs:      data.ClosedPRs,
+				Reviews:        data.Reviews,
+				Commits:        data.Commits,
+				PRStatus:       data.PRStatus,
+				WaitingOnMe:    d.waitingOnMe(user),
+				Stale:          stale,
+				StaleCount:     len(items),
+			})
+		}
+		report.Sections = append(report.Sections, section)
+	}
+	return report
+}
+
+// Activity is every user's activity, as used by the achievements report.
+func (d *Dataset) Activity() map[string][]Activity {
+	activityByUser := make(map[string][]Activity)
+	for _, user := range d.Users {
+		person := d.person(user)
+		var activities []Activity
+		for _, a := range userActivities(d.user(user)) {
+			if person.Active(a.Timestamp) {
+				a.User = user
+				if d.classes != nil {
+					a.Class = d.classOf(a.Owner, a.Repo)
+				}
+				activities = append(activities, a)
+			}
+		}
+		activityByUser[user] = activities
+	}
+	return activityByUser
+}
+
+func (d *Dataset) AchievementsReport() AchievementsReport {
+	grouped, months := GroupMonthlyActivity(d.Activity())
+	report := newAchievementsReport("Team Achievements", grouped, months, d.groups(), true, d.person)
+	report.Classes = d.classLinks("team_achievements", classAchievementsPage, "")
+	return report
+}
+
+// classAchievementsReport is the achievements of the work in one class.
+func (d *Dataset) classAchievementsReport(class string) AchievementsReport {
+	c := d.byClass(class)
+	grouped, months := GroupMonthlyActivity(c.Activity())
+	report := newAchievementsReport("Team Achievements: "+classTitle(class), grouped, months, c.groups(), false, c.person)
+	report.Classes = d.classLinks("team_achievements", classAchievementsPage, class)
+	return report
+}
+
+func (d *Dataset) teamAchievementsReport(g userGroup) AchievementsReport {
+	grouped, months := GroupMonthlyActivity(d.Activity())
+	return newAchievementsReport("Team Achievements: "+g.Team.Name, grouped, months, []userGroup{g}, false, d.person)
+}
+
+// TeamsReport totals each team's work and rolls it up over every fetched user.
+// Activity counts cover the month the data was fetched in.
+func (d *Dataset) TeamsReport() TeamsReport {
+	activity := d.Activity()
+	report := TeamsReport{Month: d.FetchedAt.Format("2006-01")}
+	for _, g := range d.teamGroups() {
+		summary := d.teamSummary(g.Users, activity, report.Month)
+		summary.Team = g.Team.Name
+		summary.Page = g.page(teamDashboardPage)
+		summary.Leads = d.displayNames(g.Team.Leads)
+		report.Teams = append(report.Teams, summary)
+	}
+	report.Org = d.teamSummary(d.Users, activity, report.Month)
+	report.Org.Team = "All users"
+	return report
+}
+
+// teamSummary counts the distinct issues and PRs of users, so one shared by two members counts once.
+func (d *Dataset) teamSummary(users []string, activity map[string][]Activity, month string) TeamSummary {
+	distinct := func(bucket func(*UserData) []Issue) []Issue {
+		seen := make(map[string]bool)
+		var issues []Issue
+		for _, user := range users {
+			for _, issue := range bucket(d.user(user)) {
+				if !seen[issue.URL] {
+					seen[issue.URL] = true
+					issues = append(issues, issue)
+				}
+			}
+		}
+		return issues
+	}
+
+	summary := TeamSummary{
+		Members:        len(users),
+		AssignedIssues: len(distinct(func(data *UserData) []Issue { return data.AssignedIssues })),
+		CreatedIssues:  len(distinct(func(data *UserData) []Issue { return data.CreatedIssues })),
+		OpenPRs:        len(distinct(func(data *UserData) []Issue { return data.OpenPRs })),
+	}
+	for _, pr := range distinct(func(data *UserData) []Issue { return data.ClosedPRs }) {
+		summary.ClosedPRs++
+		if pr.Merged() {
+			summary.MergedPRs++
+		}
+	}
+	for _, user := range users {
+		for _, a := range activity[user] {
+			if a.Timestamp.Format("2006-01") == month {
+				summary.Activity++
+			}
+		}
+	}
+	return summary
+}
+
+func (d *Dataset) KubernetesReport() KubernetesReport {
+	prsByUser := make(map[string][]Issue)
+	for _, user := range d.Users {
+		prsByUser[user] = d.user(user).KubernetesPRs
+	}
+	report := NewKubernetesReport(prsByUser)
+	for i := range report.Users {
+		report.Users[i].Name = d.person(report.Users[i].User).DisplayName()
+	}
+	sort.SliceStable(report.Users, func(i, j int) bool {
+		return report.Users[i].Name < report.Users[j].Name
+	})
+	return report
+}
@@ -0,0 +1,14 @@
+package oslib
+
+import "testing"
+
+func TestPerItemDataIsOptIn(t *testing.T) {
+	perItem := NeedReviews | NeedComments | NeedWaiting | NeedPRCycles
+	if got := NeedsFor(Reports) &^ (&Config{}).optedOut(); got&perItem != 0 {
+		t.Errorf("every report without opting in needs %b of the per-item data", got&perItem)
+	}
+	all := &Config{TrackReviews: true, TrackComments: true, DashboardWaiting: true, CycleTime: true}
+	if got := NeedsFor(Reports) &^ all.optedOut(); got&perItem != perItem {
+		t.Errorf("opting in to everything still leaves out %b", perItem&^got)
+	}
+}
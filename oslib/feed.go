@@ -0,0 +1,151 @@
+package oslib
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Feed file extensions written next to each label page.
+const (
+	AtomExt     = ".atom"
+	JSONFeedExt = ".feed.json"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Link      atomLink `xml:"link"`
+	Published string   `xml:"published"`
+	Updated   string   `xml:"updated"`
+	Summary   string   `xml:"summary"`
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	ContentText   string `json:"content_text"`
+	DatePublished string `json:"date_published"`
+	DateModified  string `json:"date_modified"`
+}
+
+// feedID is an RFC 4151 tag URI for the feed name published under siteURL.
+// The path is kept so trackers sharing a host, as on github.io, get distinct IDs.
+func feedID(siteURL, name string) (string, error) {
+	u, err := url.Parse(siteURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("site_url %q has no host", siteURL)
+	}
+	return "tag:" + u.Hostname() + ",2026:" + u.EscapedPath() + name, nil
+}
+
+// WriteFeeds writes an Atom and a JSON Feed file for issues as dir/<name>.atom and dir/<name>.feed.json.
+// siteURL is where dir is published; the feed ID and links are built from it.
+// Entry IDs are the issue URLs so readers recognise items they have already seen.
+func WriteFeeds(dir, siteURL, name, title string, issues []Issue) error {
+	id, err := feedID(siteURL, name)
+	if err != nil {
+		return err
+	}
+
+	atom := atomFeed{
+		ID:      id,
+		Title:   title,
+		Updated: feedUpdated(issues),
+		Author:  atomAuthor{Name: "open-source-tracker"},
+		Links: []atomLink{
+			{Href: siteURL + name + AtomExt, Rel: "self", Type: "application/atom+xml"},
+			{Href: siteURL + name + ".html", Rel: "alternate", Type: "text/html"},
+		},
+	}
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       title,
+		HomePageURL: siteURL + name + ".html",
+		FeedURL:     siteURL + name + JSONFeedExt,
+		Items:       []jsonFeedItem{},
+	}
+	for _, issue := range issues {
+		summary := "Open issue in " + issue.Repo
+		atom.Entries = append(atom.Entries, atomEntry{
+			ID:        issue.URL,
+			Title:     issue.Title,
+			Link:      atomLink{Href: issue.URL},
+			Published: issue.CreatedAt,
+			Updated:   issue.UpdatedAt,
+			Summary:   summary,
+		})
+		feed.Items = append(feed.Items, jsonFeedItem{
+			ID:            issue.URL,
+			URL:           issue.URL,
+			Title:         issue.Title,
+			ContentText:   summary,
+			DatePublished: issue.CreatedAt,
+			DateModified:  issue.UpdatedAt,
+		})
+	}
+
+	atomData, err := xml.MarshalIndent(atom, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+AtomExt), append([]byte(xml.Header), atomData...), 0644); err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+JSONFeedExt), jsonData, 0644)
+}
+
+// feedUpdated is the most recent update among issues, so the feed timestamp only moves when an entry does.
+func feedUpdated(issues []Issue) string {
+	latest := ""
+	for _, issue := range issues {
+		if issue.UpdatedAt > latest {
+			latest = issue.UpdatedAt
+		}
+	}
+	if latest == "" {
+		return time.Now().UTC().Format(time.RFC3339)
+	}
+	return latest
+}
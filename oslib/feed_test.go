@@ -0,0 +1,62 @@
+package oslib
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFeedsIDs(t *testing.T) {
+	issues := []Issue{{Title: "Fix it", URL: "https://github.com/o/r/issues/1", Repo: "r", UpdatedAt: "2026-01-02T00:00:00Z"}}
+	tests := []struct {
+		name        string
+		siteURL     string
+		wantID      string
+		wantFeedURL string
+	}{
+		{"project site", "https://example.github.io/tracker/", "tag:example.github.io,2026:/tracker/gfi", "https://example.github.io/tracker/gfi.feed.json"},
+		{"root site with port", "http://localhost:8080/", "tag:localhost,2026:/gfi", "http://localhost:8080/gfi.feed.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := WriteFeeds(dir, tt.siteURL, "gfi", "Good first issues", issues); err != nil {
+				t.Fatal(err)
+			}
+
+			var atom atomFeed
+			readFeed(t, filepath.Join(dir, "gfi"+AtomExt), func(b []byte) error { return xml.Unmarshal(b, &atom) })
+			if atom.ID != tt.wantID || len(atom.Links) != 2 {
+				t.Errorf("atom id %q with %d links, want %q with 2", atom.ID, len(atom.Links), tt.wantID)
+			}
+			if len(atom.Entries) != 1 || atom.Entries[0].ID != issues[0].URL {
+				t.Errorf("atom entries = %+v, want the issue URL as ID", atom.Entries)
+			}
+
+			var feed jsonFeed
+			readFeed(t, filepath.Join(dir, "gfi"+JSONFeedExt), func(b []byte) error { return json.Unmarshal(b, &feed) })
+			if feed.FeedURL != tt.wantFeedURL {
+				t.Errorf("feed_url = %q, want %q", feed.FeedURL, tt.wantFeedURL)
+			}
+		})
+	}
+}
+
+func TestWriteFeedsNeedsSiteURL(t *testing.T) {
+	if err := WriteFeeds(t.TempDir(), "", "gfi", "Good first issues", nil); err == nil {
+		t.Error("WriteFeeds without a site URL succeeded, want an error")
+	}
+}
+
+func readFeed(t *testing.T, path string, decode func([]byte) error) {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decode(b); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,27 +1,389 @@
 package oslib
 
 import (
-	"encoding/json"
-	"io/ioutil"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Users  []string `json:"users"`
-	Orgs   []string `json:"orgs"`
-	Labels []string `json:"labels"`
+	Users  []User   `json:"users" yaml:"users"`
+	Orgs   []string `json:"orgs" yaml:"orgs"`
+	Labels []Label  `json:"labels" yaml:"labels"`
+	// Teams group users into per-team sections and pages. Their members are
+	// tracked whether or not they are also listed under users.
+	Teams []Team `json:"teams" yaml:"teams"`
+	// GitHubTeams are "org/team-slug" names whose members are tracked, looked up on every run.
+	GitHubTeams []string `json:"github_teams" yaml:"github_teams"`
+	// ExcludeUsers are never tracked, even when a team lists them.
+	ExcludeUsers []string `json:"exclude_users" yaml:"exclude_users"`
+	// Filters are named repo filter profiles. The "default" profile applies to
+	// every report that ReportFilters, keyed by report name, does not give another.
+	Filters       map[string]RepoFilter `json:"filters" yaml:"filters"`
+	ReportFilters map[string]string     `json:"report_filters" yaml:"report_filters"`
+
+	// SiteURL is where the docs/ directory is published; feed links are built from it.
+	SiteURL string `json:"site_url" yaml:"site_url"`
+	// FeedsPerOrg additionally writes one feed per org and label.
+	FeedsPerOrg bool `json:"feeds_per_org" yaml:"feeds_per_org"`
+	// Stale sets when open PRs and assigned issues are flagged as needing attention.
+	Stale *Staleness `json:"stale" yaml:"stale"`
+	// DashboardCommits adds each user's commits to the dashboards. They always count as activity.
+	DashboardCommits bool `json:"dashboard_commits" yaml:"dashboard_commits"`
+	// DashboardPRStatus puts review, CI and merge badges on the open PRs of the dashboards.
+	DashboardPRStatus bool `json:"dashboard_pr_status" yaml:"dashboard_pr_status"`
+	// DashboardWaiting starts each user's dashboard with the review requests, mentions and assigned PRs waiting on them.
+	DashboardWaiting bool `json:"dashboard_waiting" yaml:"dashboard_waiting"`
+	// TrackReviews and TrackComments count reviews and comments as activity, and list reviews on the dashboards.
+	TrackReviews  bool `json:"track_reviews" yaml:"track_reviews"`
+	TrackComments bool `json:"track_comments" yaml:"track_comments"`
+	// CycleTime generates the PR cycle time page.
+	CycleTime bool `json:"cycle_time" yaml:"cycle_time"`
+	// Scoring weighs activity for the leaderboard, which is only generated when it is set.
+	Scoring *Scoring `json:"scoring" yaml:"scoring"`
+	// Classes declare which orgs and repos count as internal, upstream or ecosystem work.
+	Classes map[string]WorkClass `json:"classes" yaml:"classes"`
+}
+
+// defaultLabels are published when the config lists no labels.
+var defaultLabels = []Label{
+	{Name: "help wanted", Title: "Help Wanted", File: "help_wanted"},
+	{Name: "good first issue", Title: "Good First Issues", File: "good_first_issues"},
 }
 
+// Environment variables that override config values. List values are comma-separated.
+const (
+	EnvUsers       = "TRACKER_USERS"
+	EnvOrgs        = "TRACKER_ORGS"
+	EnvLabels      = "TRACKER_LABELS"
+	EnvSiteURL     = "TRACKER_SITE_URL"
+	EnvFeedsPerOrg = "TRACKER_FEEDS_PER_ORG"
+	EnvGitHubTeams = "TRACKER_GITHUB_TEAMS"
+	EnvExclude     = "TRACKER_EXCLUDE_USERS"
+)
+
+// LoadConfig reads a JSON or YAML config, rejecting unknown fields, then
+// applies defaults and environment overrides and validates the result.
+// Problems are reported with the line they were found on.
 func LoadConfig(filename string) (*Config, error) {
-	data, err := ioutil.ReadFile(filename)
+	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
+	// JSON is valid YAML, so one parser handles both and keeps line numbers for either.
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, fmt.Errorf("%s: config is empty", filename)
+	}
+	doc := root.Content[0]
+
+	errs := &ConfigErrors{File: filename}
+	checkKnownFields(doc, reflect.TypeOf(Config{}), "", errs)
+	if errs.Len() > 0 {
+		return nil, errs
+	}
+
 	var config Config
-	err = json.Unmarshal(data, &config)
+	if err := doc.Decode(&config); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	config.applyDefaults()
+	overridden, err := config.applyEnv()
 	if err != nil {
 		return nil, err
 	}
 
+	config.validate(configLocator{doc: doc, overridden: overridden}, errs)
+	if errs.Len() > 0 {
+		return nil, errs
+	}
+	config.addTeamMembers()
+	config.excludeUsers()
 	return &config, nil
 }
+
+func (c *Config) applyDefaults() {
+	if c.Stale == nil {
+		stale := defaultStaleness
+		c.Stale = &stale
+	}
+	if c.Labels == nil {
+		c.Labels = append([]Label(nil), defaultLabels...)
+	}
+	if c.SiteURL != "" && !strings.HasSuffix(c.SiteURL, "/") {
+		c.SiteURL += "/"
+	}
+}
+
+// applyEnv applies environment overrides and returns the config keys they replaced.
+func (c *Config) applyEnv() (map[string]string, error) {
+	overridden := make(map[string]string)
+	list := func(env, key string, dst *[]string) {
+		if value, ok := os.LookupEnv(env); ok {
+			*dst = splitList(value)
+			overridden[key] = env
+		}
+	}
+	if value, ok := os.LookupEnv(EnvUsers); ok {
+		c.Users = nil
+		c.addLogins(splitList(value))
+		overridden["users"] = EnvUsers
+	}
+	list(EnvOrgs, "orgs", &c.Orgs)
+	list(EnvGitHubTeams, "github_teams", &c.GitHubTeams)
+	list(EnvExclude, "exclude_users", &c.ExcludeUsers)
+
+	if value, ok := os.LookupEnv(EnvLabels); ok {
+		c.Labels = nil
+		for _, name := range splitList(value) {
+			c.Labels = append(c.Labels, Label{Name: name})
+		}
+		overridden["labels"] = EnvLabels
+	}
+	if value, ok := os.LookupEnv(EnvSiteURL); ok {
+		c.SiteURL = value
+		overridden["site_url"] = EnvSiteURL
+		c.applyDefaults()
+	}
+	if value, ok := os.LookupEnv(EnvFeedsPerOrg); ok {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q is not true or false", EnvFeedsPerOrg, value)
+		}
+		c.FeedsPerOrg = b
+		overridden["feeds_per_org"] = EnvFeedsPerOrg
+	}
+	return overridden, nil
+}
+
+// addTeamMembers appends every team lead and member not already listed to Users.
+func (c *Config) addTeamMembers() {
+	for _, team := range c.Teams {
+		c.addLogins(team.AllMembers())
+	}
+}
+
+// addLogins appends a user for every login that is not already one's login or alias.
+func (c *Config) addLogins(logins []string) {
+	for _, login := range logins {
+		if _, ok := c.person(login); !ok {
+			c.Users = append(c.Users, User{Login: login})
+		}
+	}
+}
+
+// Logins is the main login of every user.
+func (c *Config) Logins() []string {
+	logins := make([]string, 0, len(c.Users))
+	for _, u := range c.Users {
+		logins = append(logins, u.Login)
+	}
+	return logins
+}
+
+// person finds the user owning login, as their main login or an alias.
+func (c *Config) person(login string) (User, bool) {
+	return findUser(c.Users, login)
+}
+
+func findUser(users []User, login string) (User, bool) {
+	for _, u := range users {
+		if u.Has(login) {
+			return u, true
+		}
+	}
+	return User{}, false
+}
+
+// excludeUsers drops every excluded user from Users and from the teams.
+func (c *Config) excludeUsers() {
+	if len(c.ExcludeUsers) == 0 {
+		return
+	}
+	keep := func(logins []string) []string {
+		var kept []string
+		for _, login := range logins {
+			if !containsFold(c.ExcludeUsers, login) {
+				kept = append(kept, login)
+			}
+		}
+		return kept
+	}
+	var users []User
+	for _, u := range c.Users {
+		if !containsFold(c.ExcludeUsers, u.Login) {
+			u.Aliases = keep(u.Aliases)
+			users = append(users, u)
+		}
+	}
+	c.Users = users
+	for i := range c.Teams {
+		c.Teams[i].Leads = keep(c.Teams[i].Leads)
+		c.Teams[i].Members = keep(c.Teams[i].Members)
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// User is one tracked person. In config it is either a plain login or an
+// object naming the person and any other accounts whose work counts as theirs.
+type User struct {
+	Login string `json:"login" yaml:"login"`
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Aliases are the person's other GitHub logins; their work is merged into Login's.
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// ActiveFrom and ActiveTo are inclusive YYYY-MM-DD dates bounding the activity that counts.
+	ActiveFrom string `json:"active_from,omitempty" yaml:"active_from,omitempty"`
+	ActiveTo   string `json:"active_to,omitempty" yaml:"active_to,omitempty"`
+	Avatar     string `json:"avatar,omitempty" yaml:"avatar,omitempty"`
+}
+
+func (u *User) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*u = User{Login: node.Value}
+		return nil
+	}
+	type plain User
+	return node.Decode((*plain)(u))
+}
+
+// DisplayName is the configured name, or the login itself.
+func (u User) DisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.Login
+}
+
+// Logins is the main login followed by the aliases.
+func (u User) Logins() []string {
+	return append([]string{u.Login}, u.Aliases...)
+}
+
+// Has reports whether login is one of the user's accounts.
+func (u User) Has(login string) bool {
+	return containsFold(u.Logins(), login)
+}
+
+// Active reports whether t falls between ActiveFrom and the end of ActiveTo.
+// Dates that do not parse were rejected when the config was loaded and are ignored here.
+func (u User) Active(t time.Time) bool {
+	if from, err := time.Parse(dateLayout, u.ActiveFrom); err == nil && t.Before(from) {
+		return false
+	}
+	if to, err := time.Parse(dateLayout, u.ActiveTo); err == nil && !t.Before(to.AddDate(0, 0, 1)) {
+		return false
+	}
+	return true
+}
+
+const dateLayout = "2006-01-02"
+
+// Label is one issue label to publish. In config it is either a plain string
+// or an object with an optional display title and output file name.
+type Label struct {
+	Name  string `json:"name" yaml:"name"`
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	File  string `json:"file,omitempty" yaml:"file,omitempty"`
+	// Unassigned, NoLinkedPR and UpdatedWithinDays keep only issues still free
+	// to pick up: nobody assigned, no PR linked and recent activity.
+	Unassigned        bool `json:"unassigned,omitempty" yaml:"unassigned,omitempty"`
+	NoLinkedPR        bool `json:"no_linked_pr,omitempty" yaml:"no_linked_pr,omitempty"`
+	UpdatedWithinDays int  `json:"updated_within_days,omitempty" yaml:"updated_within_days,omitempty"`
+	// DetectClaims reads every issue's comments for someone asking to work on
+	// it, at one API call per issue. Claimed issues are listed apart.
+	DetectClaims bool `json:"detect_claims,omitempty" yaml:"detect_claims,omitempty"`
+}
+
+func (l *Label) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = Label{Name: node.Value}
+		return nil
+	}
+	type plain Label
+	return node.Decode((*plain)(l))
+}
+
+// DisplayName is the configured title, or the label name itself.
+func (l Label) DisplayName() string {
+	if l.Title != "" {
+		return l.Title
+	}
+	return l.Name
+}
+
+// Slug is the output file base name: the configured file, or the label name
+// lower-cased with every run of other characters replaced by an underscore.
+func (l Label) Slug() string {
+	if l.File != "" {
+		return l.File
+	}
+	return slugify(l.Name)
+}
+
+// Team is a named group of users, some of whom may lead it.
+type Team struct {
+	Name    string   `json:"name" yaml:"name"`
+	Leads   []string `json:"leads,omitempty" yaml:"leads,omitempty"`
+	Members []string `json:"members" yaml:"members"`
+	// GitHubTeam is an "org/team-slug" whose members join Members on every run.
+	GitHubTeam string `json:"github_team,omitempty" yaml:"github_team,omitempty"`
+}
+
+// Slug is the suffix of the team's page names, derived from its name like a label's.
+func (t Team) Slug() string {
+	return slugify(t.Name)
+}
+
+// AllMembers is the leads followed by the other members, each once.
+func (t Team) AllMembers() []string {
+	var all []string
+	for _, login := range append(append([]string(nil), t.Leads...), t.Members...) {
+		if !containsFold(all, login) {
+			all = append(all, login)
+		}
+	}
+	return all
+}
+
+// IsLead reports whether login leads the team.
+func (t Team) IsLead(login string) bool {
+	return containsFold(t.Leads, login)
+}
+
+// slugify lower-cases s and joins its runs of letters and digits with underscores.
+func slugify(s string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			sep = false
+		} else {
+			sep = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package oslib
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestUserActive(t *testing.T) {
+	at := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	bounded := User{Login: "octocat", ActiveFrom: "2026-03-01", ActiveTo: "2026-06-30"}
+	tests := []struct {
+		name string
+		user User
+		at   string
+		want bool
+	}{
+		{"no dates", User{Login: "octocat"}, "2001-01-01T00:00:00Z", true},
+		{"before active_from", bounded, "2026-02-28T23:59:59Z", false},
+		{"start of active_from", bounded, "2026-03-01T00:00:00Z", true},
+		{"inside", bounded, "2026-04-15T12:00:00Z", true},
+		{"end of active_to", bounded, "2026-06-30T23:59:59Z", true},
+		{"day after active_to", bounded, "2026-07-01T00:00:00Z", false},
+		{"only active_from", User{ActiveFrom: "2026-03-01"}, "2030-01-01T00:00:00Z", true},
+		{"only active_to", User{ActiveTo: "2026-06-30"}, "2001-01-01T00:00:00Z", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.Active(at(tt.at)); got != tt.want {
+				t.Errorf("Active(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserFromYAML(t *testing.T) {
+	var users []User
+	doc := "- octocat\n- login: hubot\n  name: Hubot\n  aliases: [hubot-work]\n"
+	if err := yaml.Unmarshal([]byte(doc), &users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].Login != "octocat" || users[1].DisplayName() != "Hubot" || !users[1].Has("HUBOT-WORK") {
+		t.Errorf("users = %+v", users)
+	}
+	if users[0].DisplayName() != "octocat" || users[0].Has("hubot") {
+		t.Errorf("plain user = %+v", users[0])
+	}
+}
+
+func TestAliasActivityCountsForMainLogin(t *testing.T) {
+	person := User{Login: "octocat", Aliases: []string{"octo-work"}, ActiveFrom: "2026-01-01"}
+	main := &UserData{CreatedIssues: []Issue{{URL: "https://github.com/o/r/issues/1", CreatedAt: "2026-02-01T00:00:00Z"}}}
+	alias := &UserData{
+		CreatedIssues: []Issue{
+			{URL: "https://github.com/o/r/issues/2", CreatedAt: "2026-03-01T00:00:00Z"},
+			// Before the person's active dates, so it does not count.
+			{URL: "https://github.com/o/r/issues/3", CreatedAt: "2025-12-31T00:00:00Z"},
+		},
+		OpenPRs: []Issue{{URL: "https://github.com/o/r/pull/4", CreatedAt: "2026-03-02T00:00:00Z", PullRequest: &PullRequestRef{}}},
+	}
+	main.merge(alias)
+
+	d := &Dataset{
+		Users:    []string{"octocat"},
+		People:   map[string]User{"octocat": person},
+		UserData: map[string]*UserData{"octocat": main},
+	}
+	activity := d.Activity()
+	if len(activity) != 1 || len(activity["octocat"]) != 3 {
+		t.Fatalf("activity = %+v, want the 3 active items under octocat", activity)
+	}
+	for _, a := range activity["octocat"] {
+		if a.User != "octocat" {
+			t.Errorf("%s credited to %q", a.URL, a.User)
+		}
+	}
+	if got := d.person("octo-work").Login; got != "octocat" {
+		t.Errorf("person(alias) = %q, want octocat", got)
+	}
+}
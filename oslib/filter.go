@@ -0,0 +1,302 @@
+package oslib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RepoFilter limits a report to work in some repositories. Orgs and repos are
+// matched ignoring case; repos are written owner/name.
+type RepoFilter struct {
+	// IncludeOrgs and IncludeRepos, when either is set, are the only places work counts in.
+	IncludeOrgs  []string `json:"include_orgs" yaml:"include_orgs"`
+	IncludeRepos []string `json:"include_repos" yaml:"include_repos"`
+	ExcludeOrgs  []string `json:"exclude_orgs" yaml:"exclude_orgs"`
+	ExcludeRepos []string `json:"exclude_repos" yaml:"exclude_repos"`
+	// ExcludeForks and ExcludeArchived need each repo looked up, once per run.
+	ExcludeForks    bool `json:"exclude_forks" yaml:"exclude_forks"`
+	ExcludeArchived bool `json:"exclude_archived" yaml:"exclude_archived"`
+	// ExcludeUserRepos drops work in repos owned by the user it is reported for.
+	ExcludeUserRepos bool `json:"exclude_user_repos" yaml:"exclude_user_repos"`
+}
+
+// Filter profile names with a meaning of their own.
+const (
+	DefaultFilter = "default"
+	NoFilter      = "none"
+)
+
+// filterName is the profile report uses: its entry in ReportFilters, else
+// "default" when that profile exists, else none.
+func (c *Config) filterName(report string) string {
+	if name, ok := c.ReportFilters[report]; ok {
+		return name
+	}
+	if _, ok := c.Filters[DefaultFilter]; ok {
+		return DefaultFilter
+	}
+	return NoFilter
+}
+
+// filterFor is the filter of report, or nil when it is unfiltered.
+func (c *Config) filterFor(report string) *RepoFilter {
+	if c == nil {
+		return nil
+	}
+	f, ok := c.Filters[c.filterName(report)]
+	if !ok {
+		return nil
+	}
+	return &f
+}
+
+// apiFilter is the filter the JSON API applies: the default profile, if any.
+func (c *Config) apiFilter() *RepoFilter {
+	if c == nil {
+		return nil
+	}
+	f, ok := c.Filters[DefaultFilter]
+	if !ok {
+		return nil
+	}
+	return &f
+}
+
+// searchFilter is the filter every report needing user data shares, if any,
+// so its qualifiers can narrow the searches themselves.
+func (c *Config) searchFilter(reports []string) *RepoFilter {
+	if c == nil {
+		return nil
+	}
+	shared := ""
+	for _, report := range reports {
+		if reportNeeds[report]&needUserData == 0 {
+			continue
+		}
+		name := c.filterName(report)
+		if shared != "" && name != shared {
+			return nil
+		}
+		shared = name
+	}
+	return c.filterFor(shared)
+}
+
+// needsRepoInfo reports whether any of reports filters on what only the repo itself tells.
+func (c *Config) needsRepoInfo(reports []string) bool {
+	for _, report := range reports {
+		if f := c.filterFor(report); f != nil && (f.ExcludeForks || f.ExcludeArchived) {
+			return true
+		}
+	}
+	return false
+}
+
+// qualify adds the filter's search qualifiers to a search for login's work.
+// Forks cannot be searched for and are only filtered afterwards.
+func (f *RepoFilter) qualify(q SearchQuery, login string) SearchQuery {
+	if f == nil {
+		return q
+	}
+	for _, org := range f.IncludeOrgs {
+		q = q.Org(org)
+	}
+	for _, repo := range f.IncludeRepos {
+		q = q.Repo(repo)
+	}
+	for _, org := range f.ExcludeOrgs {
+		q = q.Without("org", org)
+	}
+	for _, repo := range f.ExcludeRepos {
+		q = q.Without("repo", repo)
+	}
+	if f.ExcludeArchived {
+		q = q.With("archived", "false")
+	}
+	if f.ExcludeUserRepos {
+		q = q.Without("user", login)
+	}
+	return q
+}
+
+// qualifyCommits is qualify for commit searches, which know nothing of archived repos.
+func (f *RepoFilter) qualifyCommits(q SearchQuery, login string) SearchQuery {
+	if f == nil {
+		return q
+	}
+	commits := *f
+	commits.ExcludeArchived = false
+	return commits.qualify(q, login)
+}
+
+// allows reports whether f keeps issue, found for a user with logins.
+func (f *RepoFilter) allows(issue Issue, logins []string, repos map[string]RepoInfo) bool {
+	full := issue.FullRepo()
+	if len(f.IncludeOrgs)+len(f.IncludeRepos) > 0 && !containsFold(f.IncludeOrgs, issue.Owner) && !containsFold(f.IncludeRepos, full) {
+		return false
+	}
+	if containsFold(f.ExcludeOrgs, issue.Owner) || containsFold(f.ExcludeRepos, full) {
+		return false
+	}
+	if f.ExcludeUserRepos && containsFold(logins, issue.Owner) {
+		return false
+	}
+	if info, ok := repos[strings.ToLower(full)]; ok {
+		if (f.ExcludeForks && info.Fork) || (f.ExcludeArchived && info.Archived) {
+			return false
+		}
+	}
+	return true
+}
+
+// RepoInfo is what a filter needs to know about a repo beyond its name.
+type RepoInfo struct {
+	Fork     bool `json:"fork"`
+	Archived bool `json:"archived"`
+}
+
+// FetchRepoInfo looks up an owner/name repo.
+func FetchRepoInfo(ctx context.Context, repo, token string) (RepoInfo, error) {
+	owner, name, _ := strings.Cut(repo, "/")
+	var info RepoInfo
+	err := getGitHubJSON(ctx, fmt.Sprintf("https://api.github.com/repos/%s/%s", url.PathEscape(owner), url.PathEscape(name)), token, &info)
+	return info, err
+}
+
+// fetchRepoInfo looks up every repo the dataset has work in. Repos that are
+// gone or private are left out, and kept by every filter.
+func (d *Dataset) fetchRepoInfo(ctx context.Context, token string) error {
+	d.Repos = make(map[string]RepoInfo)
+	visit := func(issues []Issue) error {
+		for _, issue := range issues {
+			key := strings.ToLower(issue.FullRepo())
+			if _, ok := d.Repos[key]; ok || issue.Owner == "" {
+				continue
+			}
+			info, err := FetchRepoInfo(ctx, issue.FullRepo(), token)
+			var ghErr *GitHubError
+			if errors.As(err, &ghErr) && ghErr.StatusCode == http.StatusNotFound {
+				log.Printf("Repo %s not found; keeping its work", issue.FullRepo())
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("looking up %s: %w", issue.FullRepo(), err)
+			}
+			d.Repos[key] = info
+		}
+		return nil
+	}
+	for _, user := range d.Users {
+		data := d.user(user)
+		lists := [][]Issue{data.AssignedIssues, data.CreatedIssues, data.ClosedIssues, data.OpenPRs, data.ClosedPRs, data.KubernetesPRs}
+		for _, r := range data.Reviews {
+			lists = append(lists, []Issue{r.PR})
+		}
+		for _, c := range data.Comments {
+			lists = append(lists, []Issue{c.Issue})
+		}
+		for _, c := range data.Commits {
+			lists = append(lists, []Issue{c.repoIssue()})
+		}
+		for _, c := range data.PRCycles {
+			lists = append(lists, []Issue{c.PR})
+		}
+		for _, w := range data.Waiting {
+			lists = append(lists, []Issue{w.Issue})
+		}
+		for _, list := range lists {
+			if err := visit(list); err != nil {
+				return err
+			}
+		}
+	}
+	for _, l := range d.Labels {
+		for _, issues := range l.ByOrg {
+			if err := visit(issues); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// filtered is a copy of d keeping only the issues and PRs f allows, or d itself without a filter.
+func (d *Dataset) filtered(f *RepoFilter) *Dataset {
+	if f == nil {
+		return d
+	}
+	return d.keeping(func(issue Issue, logins []string) bool {
+		return f.allows(issue, logins, d.Repos)
+	})
+}
+
+// keeping is a copy of d keeping only the work allows accepts. logins are
+// those of the user the work is reported for, or nil for label issues.
+func (d *Dataset) keeping(allows func(issue Issue, logins []string) bool) *Dataset {
+	c := d.Clone()
+	keep := func(list *[]Issue, logins []string) {
+		kept := (*list)[:0]
+		for _, issue := range *list {
+			if allows(issue, logins) {
+				kept = append(kept, issue)
+			}
+		}
+		*list = kept
+	}
+	for user, data := range c.UserData {
+		logins := d.person(user).Logins()
+		keep(&data.AssignedIssues, logins)
+		keep(&data.CreatedIssues, logins)
+		keep(&data.ClosedIssues, logins)
+		keep(&data.OpenPRs, logins)
+		keep(&data.ClosedPRs, logins)
+		keep(&data.KubernetesPRs, logins)
+		reviews := data.Reviews[:0]
+		for _, r := range data.Reviews {
+			if allows(r.PR, logins) {
+				reviews = append(reviews, r)
+			}
+		}
+		data.Reviews = reviews
+		comments := data.Comments[:0]
+		for _, c := range data.Comments {
+			if allows(c.Issue, logins) {
+				comments = append(comments, c)
+			}
+		}
+		data.Comments = comments
+		commits := data.Commits[:0]
+		for _, c := range data.Commits {
+			if allows(c.repoIssue(), logins) {
+				commits = append(commits, c)
+			}
+		}
+		data.Commits = commits
+		cycles := data.PRCycles[:0]
+		for _, c := range data.PRCycles {
+			if allows(c.PR, logins) {
+				cycles = append(cycles, c)
+			}
+		}
+		data.PRCycles = cycles
+		waiting := data.Waiting[:0]
+		for _, w := range data.Waiting {
+			if allows(w.Issue, logins) {
+				waiting = append(waiting, w)
+			}
+		}
+		data.Waiting = waiting
+	}
+	for _, l := range c.Labels {
+		for org, issues := range l.ByOrg {
+			keep(&issues, nil)
+			l.ByOrg[org] = issues
+		}
+	}
+	return c
+}
@@ -0,0 +1,84 @@
+package oslib
+
+import "testing"
+
+func TestRepoFilterAllows(t *testing.T) {
+	repos := map[string]RepoInfo{
+		"octocat/fork":    {Fork: true},
+		"kubernetes/old":  {Archived: true},
+		"kubernetes/live": {},
+	}
+	issue := func(owner, repo string) Issue { return Issue{Owner: owner, Repo: repo} }
+	tests := []struct {
+		name   string
+		filter RepoFilter
+		issue  Issue
+		want   bool
+	}{
+		{"empty filter", RepoFilter{}, issue("anyone", "anything"), true},
+		{"included org", RepoFilter{IncludeOrgs: []string{"kubernetes"}}, issue("Kubernetes", "live"), true},
+		{"outside included orgs", RepoFilter{IncludeOrgs: []string{"kubernetes"}}, issue("octocat", "fork"), false},
+		{"included repo outside included orgs", RepoFilter{IncludeOrgs: []string{"kubernetes"}, IncludeRepos: []string{"octocat/hello"}}, issue("octocat", "hello"), true},
+		{"excluded org", RepoFilter{ExcludeOrgs: []string{"kubernetes"}}, issue("kubernetes", "live"), false},
+		{"excluded repo ignoring case", RepoFilter{ExcludeRepos: []string{"Kubernetes/Live"}}, issue("kubernetes", "live"), false},
+		{"exclusion beats inclusion", RepoFilter{IncludeOrgs: []string{"kubernetes"}, ExcludeRepos: []string{"kubernetes/live"}}, issue("kubernetes", "live"), false},
+		{"user repo", RepoFilter{ExcludeUserRepos: true}, issue("octocat", "hello"), false},
+		{"someone else's repo", RepoFilter{ExcludeUserRepos: true}, issue("kubernetes", "live"), true},
+		{"fork", RepoFilter{ExcludeForks: true}, issue("octocat", "fork"), false},
+		{"archived", RepoFilter{ExcludeArchived: true}, issue("kubernetes", "old"), false},
+		{"archived kept without the option", RepoFilter{ExcludeForks: true}, issue("kubernetes", "old"), true},
+		{"repo not looked up", RepoFilter{ExcludeForks: true, ExcludeArchived: true}, issue("unknown", "repo"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.allows(tt.issue, []string{"octocat"}, repos); got != tt.want {
+				t.Errorf("allows(%s) = %v, want %v", tt.issue.FullRepo(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoFilterQualify(t *testing.T) {
+	f := &RepoFilter{
+		IncludeOrgs:      []string{"kubernetes"},
+		ExcludeRepos:     []string{"kubernetes/website"},
+		ExcludeArchived:  true,
+		ExcludeUserRepos: true,
+	}
+	q := NewSearchQuery().Author("octocat")
+	if got, want := f.qualify(q, "octocat").String(), "author:octocat org:kubernetes -repo:kubernetes/website archived:false -user:octocat"; got != want {
+		t.Errorf("qualify() = %q, want %q", got, want)
+	}
+	if got, want := f.qualifyCommits(q, "octocat").String(), "author:octocat org:kubernetes -repo:kubernetes/website -user:octocat"; got != want {
+		t.Errorf("qualifyCommits() = %q, want %q", got, want)
+	}
+	if got := (*RepoFilter)(nil).qualify(q, "octocat"); got.String() != q.String() {
+		t.Errorf("nil filter changed the query to %q", got)
+	}
+}
+
+func TestFilterFor(t *testing.T) {
+	c := &Config{
+		Filters:       map[string]RepoFilter{DefaultFilter: {ExcludeForks: true}, "upstream": {IncludeOrgs: []string{"kubernetes"}}},
+		ReportFilters: map[string]string{PageLeaderboard: "upstream", PageStale: NoFilter},
+	}
+	tests := []struct {
+		report string
+		want   string
+	}{
+		{PageDashboard, DefaultFilter},
+		{PageLeaderboard, "upstream"},
+		{PageStale, NoFilter},
+	}
+	for _, tt := range tests {
+		if got := c.filterName(tt.report); got != tt.want {
+			t.Errorf("filterName(%q) = %q, want %q", tt.report, got, tt.want)
+		}
+	}
+	if f := c.filterFor(PageStale); f != nil {
+		t.Errorf("filterFor(%q) = %+v, want nil", PageStale, f)
+	}
+	if f := (&Config{}).filterFor(PageDashboard); f != nil {
+		t.Errorf("filterFor without profiles = %+v, want nil", f)
+	}
+}
@@ -0,0 +1,105 @@
+package oslib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var httpClient = &http.Client{Timeout: 60 * time.Second}
+
+// GitHubError is a non-2xx answer from the GitHub API.
+type GitHubError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *GitHubError) Error() string {
+	return fmt.Sprintf("GitHub API returned status: %d. Body: %s", e.StatusCode, e.Body)
+}
+
+// maxRateLimitRetries caps how often one request waits out the rate limit before giving up.
+const maxRateLimitRetries = 3
+
+// getGitHubJSON GETs a GitHub API URL and decodes the JSON body into v,
+// waiting and retrying while the rate limit is exceeded. Any other error,
+// including a 403 for a missing scope or SAML, is returned right away.
+// Cancelling ctx abandons the request and any wait for the rate limit.
+func getGitHubJSON(ctx context.Context, apiURL, token string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	for attempt := 0; ; attempt++ {
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", apiURL, err)
+		}
+		recordGitHubResponse(resp)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			if err := json.Unmarshal(body, v); err != nil {
+				return fmt.Errorf("failed to unmarshal response: %w", err)
+			}
+			return nil
+		}
+
+		if wait, ok := rateLimitWait(resp, time.Now()); ok && attempt < maxRateLimitRetries {
+			log.Printf("Rate limit exceeded. Retrying in %s...", wait.Round(time.Second))
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+			continue
+		}
+
+		return &GitHubError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+}
+
+// sleep waits for d, or returns ctx's error if it is cancelled first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitWait is how long to wait before retrying a rate-limited 403 or 429:
+// as long as Retry-After asks, or until X-RateLimit-Reset when no requests
+// remain. ok is false for every other response, which retrying will not fix.
+func rateLimitWait(resp *http.Response, now time.Time) (wait time.Duration, ok bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(max(seconds, 1)) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Minute, true
+	}
+	// A second past the reset allows for clock skew.
+	return max(time.Unix(reset, 0).Sub(now)+time.Second, time.Second), true
+}
@@ -0,0 +1,188 @@
+package oslib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FetchGitHubTeamMembers lists the members of an org team named "org/team-slug".
+// The token needs the read:org scope; without it GitHub answers 403, or 404
+// for teams it hides, and the error says so.
+func FetchGitHubTeamMembers(ctx context.Context, team, token string) ([]string, error) {
+	org, slug, _ := strings.Cut(team, "/")
+	var logins []string
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("https://api.github.com/orgs/%s/teams/%s/members?per_page=100&page=%d", url.PathEscape(org), url.PathEscape(slug), page)
+		var members []struct {
+			Login string `json:"login"`
+		}
+		if err := getGitHubJSON(ctx, apiURL, token, &members); err != nil {
+			var ghErr *GitHubError
+			if errors.As(err, &ghErr) && (ghErr.StatusCode == http.StatusForbidden || ghErr.StatusCode == http.StatusNotFound) {
+				return nil, fmt.Errorf("the token lacks the read:org scope, or the team does not exist (GitHub answered %d)", ghErr.StatusCode)
+			}
+			return nil, err
+		}
+		for _, m := range members {
+			logins = append(logins, m.Login)
+		}
+		if len(members) < 100 {
+			return logins, nil
+		}
+	}
+}
+
+// ResolveTeams returns a copy of config whose users include the members of every
+// GitHub team it references, and whose teams linked to a GitHub team include that
+// team's members. Excluded users are left out of both.
+func ResolveTeams(ctx context.Context, config *Config, token string) (*Config, error) {
+	resolved := *config
+	resolved.Users = append([]User(nil), config.Users...)
+	resolved.Teams = append([]Team(nil), config.Teams...)
+
+	members := make(map[string][]string)
+	fetch := func(team string) ([]string, error) {
+		if logins, ok := members[team]; ok {
+			return logins, nil
+		}
+		logins, err := FetchGitHubTeamMembers(ctx, team, token)
+		if err != nil {
+			return nil, fmt.Errorf("fetching members of %s: %w", team, err)
+		}
+		log.Printf("GitHub team %s has %d members", team, len(logins))
+		members[team] = logins
+		return logins, nil
+	}
+
+	for _, team := range config.GitHubTeams {
+		logins, err := fetch(team)
+		if err != nil {
+			return nil, err
+		}
+		resolved.addLogins(logins)
+	}
+	for i, team := range resolved.Teams {
+		if team.GitHubTeam == "" {
+			continue
+		}
+		logins, err := fetch(team.GitHubTeam)
+		if err != nil {
+			return nil, err
+		}
+		team.Members = appendMissing(append([]string(nil), team.Members...), logins)
+		resolved.Teams[i] = team
+		resolved.addLogins(logins)
+	}
+
+	resolved.excludeUsers()
+	return &resolved, nil
+}
+
+// appendMissing appends the logins not already in list, ignoring case.
+func appendMissing(list, logins []string) []string {
+	for _, login := range logins {
+		if !containsFold(list, login) {
+			list = append(list, login)
+		}
+	}
+	return list
+}
+
+// syncsGitHubTeams reports whether any users come from GitHub teams.
+func (c *Config) syncsGitHubTeams() bool {
+	if len(c.GitHubTeams) > 0 {
+		return true
+	}
+	for _, team := range c.Teams {
+		if team.GitHubTeam != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// trackedUsersFile keeps the users of the previous run next to the reports,
+// so the next run can tell who was added or removed.
+const trackedUsersFile = "tracked_users.json"
+
+// maxUserChanges is how many past changes the user_changes page keeps.
+const maxUserChanges = 50
+
+type trackedUsers struct {
+	UpdatedAt time.Time    `json:"updated_at"`
+	Users     []string     `json:"users"`
+	Changes   []UserChange `json:"changes"`
+}
+
+// writeUserChanges compares d.Users with the previous run's, writes the
+// user_changes page and records the current users for the next run.
+func writeUserChanges(d *Dataset, out Output) error {
+	if out.Stdout() {
+		log.Printf("Skipping user_changes: it needs an output directory to remember the previous run")
+		return nil
+	}
+	if !d.allUsers {
+		log.Printf("Skipping user_changes: only some users were fetched")
+		return nil
+	}
+
+	path := filepath.Join(out.Dir, trackedUsersFile)
+	var previous trackedUsers
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, &previous); err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+
+	report := UserChangesReport{Since: previous.UpdatedAt, Users: len(d.Users), History: previous.Changes}
+	if !previous.UpdatedAt.IsZero() {
+		change := UserChange{At: d.FetchedAt, Added: missingFrom(d.Users, previous.Users), Removed: missingFrom(previous.Users, d.Users)}
+		if change.Added != nil || change.Removed != nil {
+			report.Added, report.Removed = change.Added, change.Removed
+			report.History = append([]UserChange{change}, report.History...)
+		}
+	}
+	if len(report.History) > maxUserChanges {
+		report.History = report.History[:maxUserChanges]
+	}
+
+	if err := writePage(out, Page{Kind: PageUserChanges, Name: "user_changes", Data: report}); err != nil {
+		return err
+	}
+
+	users := append([]string(nil), d.Users...)
+	sort.Strings(users)
+	data, err = json.MarshalIndent(trackedUsers{UpdatedAt: d.FetchedAt, Users: users, Changes: report.History}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// missingFrom is the logins in list that other lacks, sorted.
+func missingFrom(list, other []string) []string {
+	var missing []string
+	for _, login := range list {
+		if !containsFold(other, login) {
+			missing = append(missing, login)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,71 @@
+package oslib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		want    time.Duration
+		wantOK  bool
+	}{
+		{"ok response", http.StatusOK, nil, 0, false},
+		{"not found", http.StatusNotFound, map[string]string{"X-RateLimit-Remaining": "0"}, 0, false},
+		{"forbidden without rate limit headers", http.StatusForbidden, nil, 0, false},
+		{"forbidden with requests left", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "12"}, 0, false},
+		{"retry after", http.StatusForbidden, map[string]string{"Retry-After": "30"}, 30 * time.Second, true},
+		{"too many requests retry after zero", http.StatusTooManyRequests, map[string]string{"Retry-After": "0"}, time.Second, true},
+		{"exhausted until reset", http.StatusForbidden, map[string]string{
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     strconv.FormatInt(now.Add(90*time.Second).Unix(), 10),
+		}, 91 * time.Second, true},
+		{"exhausted with reset passed", http.StatusForbidden, map[string]string{
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     strconv.FormatInt(now.Add(-time.Minute).Unix(), 10),
+		}, time.Second, true},
+		{"exhausted without reset", http.StatusTooManyRequests, map[string]string{"X-RateLimit-Remaining": "0"}, time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+			got, ok := rateLimitWait(resp, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("rateLimitWait() = %s, %v; want %s, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRateLimitWaitStopsWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	useServer(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	var v struct{}
+	err := getGitHubJSON(ctx, "https://api.github.com/rate_limited", "", &v)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the context deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("returned after %s, want right after the deadline", elapsed)
+	}
+}
@@ -1,108 +1,138 @@
 package oslib
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"log"
-	"net/http"
+	"context"
 	"sort"
 	"strings"
 	"time"
 )
 
 type Issue struct {
-	Title     string `json:"title"`
-	URL       string `json:"html_url"`
-	Repo      string `json:"repository_url"`
+	Number int    `json:"number,omitempty"`
+	Title  string `json:"title"`
+	URL    string `json:"html_url"`
+	Repo   string `json:"repository_url"`
+	// Owner is the user or org owning Repo.
+	Owner     string `json:"owner,omitempty"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+	ClosedAt  string `json:"closed_at,omitempty"`
+	// Draft is only set for draft pull requests.
+	Draft bool `json:"draft,omitempty"`
+	// User opened the issue or PR.
+	User   *UserRef     `json:"user,omitempty"`
+	Labels []IssueLabel `json:"labels,omitempty"`
+	// PullRequest is only set for pull requests.
+	PullRequest *PullRequestRef `json:"pull_request,omitempty"`
 }
 
-func FetchClosedIssues(username, token string) []Issue {
-	apiURL := fmt.Sprintf("https://api.github.com/search/issues?q=author:%s+is:issue+is:closed", username)
-	return fetchGitHubData(apiURL, token)
+type UserRef struct {
+	Login string `json:"login"`
 }
 
-func FetchAssignedIssues(username, token string) []Issue {
-	return fetchGitHubData(fmt.Sprintf("https://api.github.com/search/issues?q=assignee:%s+is:issue+is:open", username), token)
+type IssueLabel struct {
+	Name string `json:"name"`
 }
 
-func FetchCreatedIssues(username, token string) []Issue {
-	return fetchGitHubData(fmt.Sprintf("https://api.github.com/search/issues?q=author:%s+is:issue+is:open", username), token)
+// LabelNames lists the names of the issue's labels.
+func (i Issue) LabelNames() []string {
+	var names []string
+	for _, l := range i.Labels {
+		names = append(names, l.Name)
+	}
+	return names
 }
 
-func FetchOpenPRs(username, token string) []Issue {
-	return fetchGitHubData(fmt.Sprintf("https://api.github.com/search/issues?q=author:%s+is:pr+is:open", username), token)
+type PullRequestRef struct {
+	MergedAt string `json:"merged_at,omitempty"`
 }
 
-func FetchClosedPRs(username, token string) []Issue {
-	oneYearAgo := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
-	apiURL := fmt.Sprintf(
-		"https://api.github.com/search/issues?q=author:%s+is:pr+is:closed+closed:>=%s",
-		username,
-		oneYearAgo,
-	)
-	return fetchGitHubData(apiURL, token)
+func (i Issue) Merged() bool {
+	return i.PullRequest != nil && i.PullRequest.MergedAt != ""
 }
 
-func fetchGitHubData(apiURL, token string) []Issue {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		log.Fatalf("Failed to create request: %v", err)
+// closedOn is when a closed PR was merged, or else closed; CreatedAt when neither is known.
+func (i Issue) closedOn() string {
+	switch {
+	case i.Merged():
+		return i.PullRequest.MergedAt
+	case i.ClosedAt != "":
+		return i.ClosedAt
 	}
-	req.Header.Set("Authorization", "token "+token)
+	return i.CreatedAt
+}
 
-	for {
-		resp, err := client.Do(req)
-		if err != nil {
-			log.Fatalf("Failed to fetch data: %v", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == http.StatusOK {
-			body, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				log.Fatalf("Failed to read response body: %v", err)
-			}
-
-			var result struct {
-				Items []Issue `json:"items"`
-			}
-			if err := json.Unmarshal(body, &result); err != nil {
-				log.Fatalf("Failed to unmarshal response: %v", err)
-			}
-
-			// Process repository field to extract only the repo name
-			for i := range result.Items {
-				repoParts := strings.Split(result.Items[i].Repo, "/")
-				if len(repoParts) >= 2 {
-					result.Items[i].Repo = repoParts[len(repoParts)-1]
-				}
-			}
-			//Sorting
-			sort.Slice(result.Items, func(i, j int) bool {
-				return result.Items[i].CreatedAt > result.Items[j].CreatedAt
-			})
-			return result.Items
-		}
+// Author is the login of whoever opened the issue, if known.
+func (i Issue) Author() string {
+	if i.User == nil {
+		return ""
+	}
+	return i.User.Login
+}
 
-		if resp.StatusCode == 403 {
-			log.Println("Rate limit exceeded. Retrying after 60 seconds...")
-			time.Sleep(60 * time.Second)
-			continue
-		}
+// FullRepo is the owner/name of the issue's repository.
+func (i Issue) FullRepo() string {
+	return i.Owner + "/" + i.Repo
+}
+
+func closedIssuesQuery(username string) SearchQuery {
+	return NewSearchQuery().Author(username).Is("issue").State("closed")
+}
 
-		// Log and exit for non-retryable status codes
-		body, _ := ioutil.ReadAll(resp.Body)
-		log.Fatalf("GitHub API returned status: %d. Body: %s", resp.StatusCode, body)
+func assignedIssuesQuery(username string) SearchQuery {
+	return NewSearchQuery().Assignee(username).Is("issue").State("open")
+}
+
+func createdIssuesQuery(username string) SearchQuery {
+	return NewSearchQuery().Author(username).Is("issue").State("open")
+}
+
+func openPRsQuery(username string) SearchQuery {
+	return NewSearchQuery().Author(username).Is("pr").State("open")
+}
+
+func closedPRsQuery(username string) SearchQuery {
+	oneYearAgo := time.Now().AddDate(-1, 0, 0)
+	return NewSearchQuery().Author(username).Is("pr").State("closed").Closed(Since(oneYearAgo))
+}
+
+// searchIssues runs an issue search across every page of results, newest first.
+func searchIssues(ctx context.Context, q SearchQuery, token string) ([]Issue, error) {
+	items, err := searchPages[Issue](ctx, q.URL, token)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	// Process repository field to extract only the repo name, keeping its owner apart
+	for i := range items {
+		repoParts := strings.Split(items[i].Repo, "/")
+		if len(repoParts) >= 2 {
+			items[i].Owner = repoParts[len(repoParts)-2]
+			items[i].Repo = repoParts[len(repoParts)-1]
+		}
+	}
+	//Sorting
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt > items[j].CreatedAt
+	})
+	return items, nil
+}
+
+// FetchIssues finds the open issues in org carrying label, narrowed by its freshness settings.
+func FetchIssues(ctx context.Context, org, token string, label Label) ([]Issue, error) {
+	return searchIssues(ctx, labelIssuesQuery(org, label), token)
 }
 
-func FetchIssues(org, token string, label string) []Issue {
-	apiURL := fmt.Sprintf("https://api.github.com/search/issues?q=org:%s+label:%q+is:issue+is:open", org, label)
-	return fetchGitHubData(apiURL, token)
+func labelIssuesQuery(org string, label Label) SearchQuery {
+	q := NewSearchQuery().Org(org).Label(label.Name).Is("issue").State("open")
+	if label.Unassigned {
+		q = q.With("no", "assignee")
+	}
+	if label.NoLinkedPR {
+		q = q.Without("linked", "pr")
+	}
+	if label.UpdatedWithinDays > 0 {
+		q = q.Updated(Since(time.Now().AddDate(0, 0, -label.UpdatedWithinDays)))
+	}
+	return q
 }
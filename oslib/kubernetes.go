@@ -1,20 +1,23 @@
 package oslib
 
-import "fmt"
+import "context"
 
-func FetchKubernetesPRs(users []string, token string) map[string][]Issue {
-	orgs := []string{"kubernetes", "kubernetes-sigs"}
+var kubernetesOrgs = []string{"kubernetes", "kubernetes-sigs"}
+
+func FetchKubernetesPRs(ctx context.Context, users []string, token string) (map[string][]Issue, error) {
 	result := make(map[string][]Issue)
 
 	for _, user := range users {
 		var userPRs []Issue
-		for _, org := range orgs {
-			apiURL := fmt.Sprintf("https://api.github.com/search/issues?q=org:%s+author:%s+is:pr", org, user)
-			prs := fetchGitHubData(apiURL, token)
+		for _, org := range kubernetesOrgs {
+			prs, err := searchIssues(ctx, NewSearchQuery().Org(org).Author(user).Is("pr"), token)
+			if err != nil {
+				return nil, err
+			}
 			userPRs = append(userPRs, prs...)
 		}
 		result[user] = userPRs
 	}
 
-	return result
+	return result, nil
 }
@@ -0,0 +1,160 @@
+package oslib
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// githubStats counts GitHub API responses for /metrics. It is package level
+// because every fetch goes through getGitHubJSON, whichever command runs it.
+var githubStats = struct {
+	sync.Mutex
+	requests  map[int]int
+	remaining map[string]int
+}{
+	requests:  make(map[int]int),
+	remaining: make(map[string]int),
+}
+
+func recordGitHubResponse(resp *http.Response) {
+	githubStats.Lock()
+	defer githubStats.Unlock()
+
+	githubStats.requests[resp.StatusCode]++
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		resource := resp.Header.Get("X-RateLimit-Resource")
+		if resource == "" {
+			resource = "core"
+		}
+		githubStats.remaining[resource] = remaining
+	}
+}
+
+// handleMetrics writes contribution and tracker health metrics in the Prometheus text format.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m := metricWriter{w: w}
+
+	if d := s.Dataset(); d != nil {
+		writeContributionMetrics(m, d)
+	}
+
+	githubStats.Lock()
+	m.help("tracker_github_requests_total", "counter", "GitHub API responses by HTTP status.")
+	for _, status := range sortedIntKeys(githubStats.requests) {
+		m.sample("tracker_github_requests_total", githubStats.requests[status], "status", strconv.Itoa(status))
+	}
+	m.help("tracker_github_rate_limit_remaining", "gauge", "Requests left in the current GitHub rate limit window, by resource.")
+	for _, resource := range sortedKeys(githubStats.remaining) {
+		m.sample("tracker_github_rate_limit_remaining", githubStats.remaining[resource], "resource", resource)
+	}
+	githubStats.Unlock()
+
+	s.mu.RLock()
+	m.help("tracker_refreshing", "gauge", "1 while a refresh is fetching data.")
+	m.sample("tracker_refreshing", boolMetric(s.refreshing))
+	m.help("tracker_refresh_failures_total", "counter", "Refreshes that failed and left the previous reports in place.")
+	m.sample("tracker_refresh_failures_total", s.refreshFailures)
+	m.help("tracker_fetch_duration_seconds", "gauge", "How long the last successful refresh took to fetch its data.")
+	m.sample("tracker_fetch_duration_seconds", s.lastFetchDuration.Seconds())
+	// Every refresh renders all reports together, so one timestamp covers them all.
+	m.help("tracker_last_successful_refresh_timestamp_seconds", "gauge", "Unix time the reports were last regenerated successfully.")
+	if !s.lastRefresh.IsZero() {
+		m.sample("tracker_last_successful_refresh_timestamp_seconds", s.lastRefresh.Unix())
+	}
+	s.mu.RUnlock()
+}
+
+func writeContributionMetrics(m metricWriter, d *Dataset) {
+	monthAgo := time.Now().AddDate(0, 0, -30).UTC().Format(time.RFC3339)
+
+	m.help("tracker_user_items", "gauge", "Issues and PRs per tracked user and category.")
+	for _, user := range d.Users {
+		data := d.user(user)
+		merged := 0
+		for _, pr := range data.ClosedPRs {
+			if pr.Merged() && pr.PullRequest.MergedAt >= monthAgo {
+				merged++
+			}
+		}
+		reviews := 0
+		for _, r := range data.Reviews {
+			if r.SubmittedAt >= monthAgo {
+				reviews++
+			}
+		}
+		comments := 0
+		for _, c := range data.Comments {
+			if c.CreatedAt >= monthAgo {
+				comments++
+			}
+		}
+		m.sample("tracker_user_items", len(data.OpenPRs), "user", user, "category", "open_prs")
+		m.sample("tracker_user_items", len(data.AssignedIssues), "user", user, "category", "assigned_issues")
+		m.sample("tracker_user_items", len(data.CreatedIssues), "user", user, "category", "created_issues")
+		m.sample("tracker_user_items", merged, "user", user, "category", "merged_prs_30d")
+		m.sample("tracker_user_items", reviews, "user", user, "category", "reviews_30d")
+		m.sample("tracker_user_items", comments, "user", user, "category", "comments_30d")
+	}
+
+	m.help("tracker_label_open_issues", "gauge", "Open issues per configured label and org.")
+	for _, l := range d.Labels {
+		for _, org := range sortedKeys(l.ByOrg) {
+			m.sample("tracker_label_open_issues", len(l.ByOrg[org]), "label", l.Label.Name, "org", org)
+		}
+	}
+
+	m.help("tracker_data_fetched_timestamp_seconds", "gauge", "Unix time the served data was fetched from GitHub.")
+	m.sample("tracker_data_fetched_timestamp_seconds", d.FetchedAt.Unix())
+}
+
+type metricWriter struct {
+	w io.Writer
+}
+
+func (m metricWriter) help(name, kind, help string) {
+	fmt.Fprintf(m.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes one value; labels are name, value pairs.
+func (m metricWriter) sample(name string, value interface{}, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, "%s=\"%s\"", labels[i], labelEscaper.Replace(labels[i+1]))
+		}
+		b.WriteByte('}')
+	}
+	fmt.Fprintf(m.w, "%s %v\n", b.String(), value)
+}
+
+// labelEscaper escapes a label value the way the Prometheus text format
+// defines: only backslash, double quote and newline.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func boolMetric(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sortedIntKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,27 @@
+package oslib
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMetricSampleEscapesLabels(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"kind/flake", `m{label="kind/flake"} 1` + "\n"},
+		{"café", `m{label="café"} 1` + "\n"},
+		{`say "hi"`, `m{label="say \"hi\""} 1` + "\n"},
+		{`C:\path`, `m{label="C:\\path"} 1` + "\n"},
+		{"two\nlines", `m{label="two\nlines"} 1` + "\n"},
+		{"tab\there", "m{label=\"tab\there\"} 1\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		metricWriter{w: &buf}.sample("m", 1, "label", tt.value)
+		if got := buf.String(); got != tt.want {
+			t.Errorf("sample(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package oslib
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Review states that settle where a PR's latest reviews stand.
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+)
+
+// Combined CI states. CINone means the head commit has no checks or statuses.
+const (
+	CISuccess = "success"
+	CIFailure = "failure"
+	CIPending = "pending"
+	CINone    = ""
+)
+
+// PRStatus is where an open PR stands: its reviews, checks, draft state and whether it merges cleanly.
+type PRStatus struct {
+	Draft bool `json:"draft"`
+	// LatestReviews is what the latest approval or change request of each
+	// reviewer adds up to, or empty without any. Unlike GitHub's review
+	// decision it knows nothing of required reviews or code owners.
+	LatestReviews string `json:"latest_reviews,omitempty"`
+	CI            string `json:"ci,omitempty"`
+	// MergeState is GitHub's mergeable_state: clean, dirty (conflicts), blocked, behind, unstable or unknown.
+	MergeState string `json:"merge_state"`
+}
+
+// Conflicts reports whether the PR no longer merges cleanly.
+func (s PRStatus) Conflicts() bool {
+	return s.MergeState == "dirty"
+}
+
+// WaitingOnAuthor reports whether the author has to act next: the PR is a
+// draft, has changes requested, fails CI or has conflicts. Otherwise it waits on maintainers.
+func (s PRStatus) WaitingOnAuthor() bool {
+	return s.Draft || s.LatestReviews == ReviewChangesRequested || s.CI == CIFailure || s.Conflicts()
+}
+
+// StatusBadge is one part of a PRStatus as shown on the dashboard. Tone is
+// "ok", "bad", "wait" or "info", which renderers map to their own styles.
+type StatusBadge struct {
+	Label string
+	Tone  string
+}
+
+// Badges lists the parts of the status worth showing, ending with who the PR
+// waits on. A status that was never fetched has none.
+func (s PRStatus) Badges() []StatusBadge {
+	if s == (PRStatus{}) {
+		return nil
+	}
+	var badges []StatusBadge
+	if s.Draft {
+		badges = append(badges, StatusBadge{"Draft", "info"})
+	}
+	switch s.LatestReviews {
+	case ReviewApproved:
+		badges = append(badges, StatusBadge{"Approved", "ok"})
+	case ReviewChangesRequested:
+		badges = append(badges, StatusBadge{"Changes requested", "bad"})
+	}
+	switch s.CI {
+	case CISuccess:
+		badges = append(badges, StatusBadge{"CI passing", "ok"})
+	case CIFailure:
+		badges = append(badges, StatusBadge{"CI failing", "bad"})
+	case CIPending:
+		badges = append(badges, StatusBadge{"CI pending", "wait"})
+	}
+	switch s.MergeState {
+	case "dirty":
+		badges = append(badges, StatusBadge{"Conflicts", "bad"})
+	case "behind":
+		badges = append(badges, StatusBadge{"Behind base", "info"})
+	}
+	if s.WaitingOnAuthor() {
+		badges = append(badges, StatusBadge{"Waiting on author", "info"})
+	} else {
+		badges = append(badges, StatusBadge{"Waiting on maintainers", "info"})
+	}
+	return badges
+}
+
+// How often and how long FetchPRStatus waits for GitHub to work out whether a PR merges cleanly.
+const (
+	maxMergeStateRetries = 2
+	mergeStateDelay      = 2 * time.Second
+)
+
+// FetchPRStatus looks up the state of an open PR.
+func FetchPRStatus(ctx context.Context, pr Issue, token string) (PRStatus, error) {
+	var status PRStatus
+	var pull struct {
+		Draft          bool   `json:"draft"`
+		MergeableState string `json:"mergeable_state"`
+		Head           struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	repo := fmt.Sprintf("https://api.github.com/repos/%s/%s", url.PathEscape(pr.Owner), url.PathEscape(pr.Repo))
+	// GitHub works out mergeability in the background and answers "unknown" until it has.
+	for attempt := 0; ; attempt++ {
+		if err := getGitHubJSON(ctx, fmt.Sprintf("%s/pulls/%d", repo, pr.Number), token, &pull); err != nil {
+			return status, err
+		}
+		if pull.MergeableState != "unknown" || attempt == maxMergeStateRetries {
+			break
+		}
+		log.Printf("Mergeability of %s is not known yet, asking again in %s", pr.URL, mergeStateDelay)
+		if err := sleep(ctx, mergeStateDelay); err != nil {
+			return status, err
+		}
+	}
+	status.Draft = pull.Draft
+	status.MergeState = pull.MergeableState
+
+	reviews, err := fetchPRReviews(ctx, pr, token)
+	if err != nil {
+		return status, err
+	}
+	status.LatestReviews = latestReviews(reviews, pr.Author())
+
+	status.CI, err = fetchCIState(ctx, repo, pull.Head.SHA, token)
+	return status, err
+}
+
+// latestReviews is what the latest approval or change request of each reviewer
+// adds up to: changes requested by anyone, else approved, else nothing.
+func latestReviews(reviews []prReview, author string) string {
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		login := strings.ToLower(r.User.Login)
+		switch {
+		case login == strings.ToLower(author):
+		case r.State == "APPROVED" || r.State == "CHANGES_REQUESTED":
+			latest[login] = r.State
+		case r.State == "DISMISSED":
+			delete(latest, login)
+		}
+	}
+	decision := ""
+	for _, state := range latest {
+		switch state {
+		case ReviewChangesRequested:
+			return ReviewChangesRequested
+		case ReviewApproved:
+			decision = ReviewApproved
+		}
+	}
+	return decision
+}
+
+// fetchCIState combines the commit statuses and check runs of a commit.
+func fetchCIState(ctx context.Context, repo, sha, token string) (string, error) {
+	if sha == "" {
+		return CINone, nil
+	}
+	var combined struct {
+		State      string `json:"state"`
+		TotalCount int    `json:"total_count"`
+	}
+	if err := getGitHubJSON(ctx, fmt.Sprintf("%s/commits/%s/status", repo, sha), token, &combined); err != nil {
+		return CINone, err
+	}
+	type checkRun struct {
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	}
+	var runs []checkRun
+	for page := 1; ; page++ {
+		var checks struct {
+			TotalCount int        `json:"total_count"`
+			CheckRuns  []checkRun `json:"check_runs"`
+		}
+		if err := getGitHubJSON(ctx, fmt.Sprintf("%s/commits/%s/check-runs?per_page=100&page=%d", repo, sha, page), token, &checks); err != nil {
+			return CINone, err
+		}
+		runs = append(runs, checks.CheckRuns...)
+		if len(checks.CheckRuns) < 100 || len(runs) >= checks.TotalCount {
+			break
+		}
+	}
+
+	var states []string
+	if combined.TotalCount > 0 {
+		states = append(states, combined.State)
+	}
+	for _, run := range runs {
+		switch {
+		case run.Status != "completed":
+			states = append(states, CIPending)
+		case run.Conclusion == "success" || run.Conclusion == "neutral" || run.Conclusion == "skipped":
+			states = append(states, CISuccess)
+		default:
+			states = append(states, CIFailure)
+		}
+	}
+
+	ci := CINone
+	for _, state := range states {
+		switch {
+		case state == "failure" || state == "error":
+			return CIFailure, nil
+		case state == CIPending:
+			ci = CIPending
+		case ci == CINone:
+			ci = CISuccess
+		}
+	}
+	return ci, nil
+}
+
+// fetchPRStatuses looks up every PR in prs, keyed by URL.
+func fetchPRStatuses(ctx context.Context, prs []Issue, token string) (map[string]PRStatus, error) {
+	statuses := make(map[string]PRStatus)
+	for _, pr := range prs {
+		status, err := FetchPRStatus(ctx, pr, token)
+		if err != nil {
+			return nil, fmt.Errorf("fetching the status of %s: %w", pr.URL, err)
+		}
+		statuses[pr.URL] = status
+	}
+	return statuses, nil
+}
@@ -0,0 +1,82 @@
+package oslib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLatestReviews(t *testing.T) {
+	review := func(login, state string) prReview {
+		r := prReview{State: state}
+		r.User.Login = login
+		return r
+	}
+	tests := []struct {
+		name    string
+		reviews []prReview
+		want    string
+	}{
+		{"no reviews", nil, ""},
+		{"only comments", []prReview{review("alice", "COMMENTED")}, ""},
+		{"approved", []prReview{review("alice", "APPROVED")}, ReviewApproved},
+		{"changes requested wins", []prReview{review("alice", "APPROVED"), review("bob", "CHANGES_REQUESTED")}, ReviewChangesRequested},
+		{"later approval replaces a change request", []prReview{review("bob", "CHANGES_REQUESTED"), review("bob", "APPROVED")}, ReviewApproved},
+		{"dismissed", []prReview{review("bob", "CHANGES_REQUESTED"), review("bob", "DISMISSED")}, ""},
+		{"author ignored", []prReview{review("Author", "APPROVED")}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latestReviews(tt.reviews, "author"); got != tt.want {
+				t.Errorf("latestReviews() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBadgesWithoutReviews(t *testing.T) {
+	badges := PRStatus{CI: CISuccess, MergeState: "clean"}.Badges()
+	for _, b := range badges {
+		if strings.Contains(b.Label, "Review") || b.Label == "Approved" {
+			t.Errorf("a PR without reviews got the %q badge", b.Label)
+		}
+	}
+	if last := badges[len(badges)-1]; last.Label != "Waiting on maintainers" {
+		t.Errorf("last badge = %q, want who the PR waits on", last.Label)
+	}
+}
+
+func TestFetchCIStateReadsEveryCheckRunPage(t *testing.T) {
+	const total = 150
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/status") {
+			json.NewEncoder(w).Encode(map[string]interface{}{"state": "success", "total_count": 0})
+			return
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		var runs []map[string]string
+		for i := (page - 1) * 100; i < min(page*100, total); i++ {
+			conclusion := "success"
+			if i == total-1 {
+				conclusion = "failure"
+			}
+			runs = append(runs, map[string]string{"status": "completed", "conclusion": conclusion, "name": fmt.Sprint(i)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"total_count": total, "check_runs": runs})
+	}))
+	defer server.Close()
+	useServer(t, server)
+
+	ci, err := fetchCIState(context.Background(), "https://api.github.com/repos/o/r", "abc", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ci != CIFailure {
+		t.Errorf("fetchCIState() = %q, want the failure on the second page", ci)
+	}
+}
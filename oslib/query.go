@@ -0,0 +1,146 @@
+package oslib
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	searchIssuesEndpoint  = "https://api.github.com/search/issues"
+	searchCommitsEndpoint = "https://api.github.com/search/commits"
+
+	// searchPerPage is the most results one page of a search holds, and
+	// searchMaxResults the most GitHub returns for a search across its pages.
+	searchPerPage    = 100
+	searchMaxResults = 1000
+)
+
+// SearchQuery builds a GitHub search query out of qualifiers. It is a value:
+// every method returns a new query, so a common base can be shared.
+//
+//	q := NewSearchQuery().Author("octocat").Is("pr").State("open")
+//	q.Without("label", "do-not-merge/hold").String() // author:octocat is:pr state:open -label:do-not-merge/hold
+type SearchQuery struct {
+	terms []string
+}
+
+func NewSearchQuery() SearchQuery {
+	return SearchQuery{}
+}
+
+// With adds key:value, quoting the value when it contains spaces or other separators.
+func (q SearchQuery) With(key, value string) SearchQuery {
+	return q.add(key + ":" + quoteSearchValue(value))
+}
+
+// Without adds the negated qualifier -key:value.
+func (q SearchQuery) Without(key, value string) SearchQuery {
+	return q.add("-" + key + ":" + quoteSearchValue(value))
+}
+
+// Text adds a free-text search term.
+func (q SearchQuery) Text(term string) SearchQuery {
+	return q.add(quoteSearchValue(term))
+}
+
+func (q SearchQuery) Author(login string) SearchQuery    { return q.With("author", login) }
+func (q SearchQuery) Assignee(login string) SearchQuery  { return q.With("assignee", login) }
+func (q SearchQuery) Commenter(login string) SearchQuery { return q.With("commenter", login) }
+func (q SearchQuery) Org(org string) SearchQuery         { return q.With("org", org) }
+func (q SearchQuery) Repo(repo string) SearchQuery       { return q.With("repo", repo) }
+func (q SearchQuery) Label(label string) SearchQuery     { return q.With("label", label) }
+func (q SearchQuery) Is(value string) SearchQuery        { return q.With("is", value) }
+func (q SearchQuery) State(state string) SearchQuery     { return q.With("state", state) }
+func (q SearchQuery) Created(r DateRange) SearchQuery    { return q.add("created:" + r.String()) }
+func (q SearchQuery) Updated(r DateRange) SearchQuery    { return q.add("updated:" + r.String()) }
+func (q SearchQuery) Closed(r DateRange) SearchQuery     { return q.add("closed:" + r.String()) }
+func (q SearchQuery) Merged(r DateRange) SearchQuery     { return q.add("merged:" + r.String()) }
+
+// CommitterDate only applies to commit searches.
+func (q SearchQuery) CommitterDate(r DateRange) SearchQuery {
+	return q.add("committer-date:" + r.String())
+}
+
+// String is the raw query as typed into the GitHub search box.
+func (q SearchQuery) String() string {
+	return strings.Join(q.terms, " ")
+}
+
+// URL is the search API URL for a page (from 1) of this query's results, with the query properly encoded.
+func (q SearchQuery) URL(page int) string {
+	return q.pageURL(searchIssuesEndpoint, page)
+}
+
+// CommitsURL is the commit search API URL for a page of this query's results.
+func (q SearchQuery) CommitsURL(page int) string {
+	return q.pageURL(searchCommitsEndpoint, page)
+}
+
+func (q SearchQuery) pageURL(endpoint string, page int) string {
+	values := url.Values{"q": {q.String()}, "per_page": {strconv.Itoa(searchPerPage)}}
+	if page > 1 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	return endpoint + "?" + values.Encode()
+}
+
+// searchPages fetches the results of a search page by page, up to the
+// searchMaxResults GitHub allows. pageURL gives the URL of each page.
+func searchPages[T any](ctx context.Context, pageURL func(page int) string, token string) ([]T, error) {
+	var all []T
+	for page := 1; page*searchPerPage <= searchMaxResults; page++ {
+		var result struct {
+			Items []T `json:"items"`
+		}
+		if err := getGitHubJSON(ctx, pageURL(page), token, &result); err != nil {
+			return nil, err
+		}
+		all = append(all, result.Items...)
+		if len(result.Items) < searchPerPage {
+			break
+		}
+	}
+	return all, nil
+}
+
+func (q SearchQuery) add(term string) SearchQuery {
+	terms := make([]string, len(q.terms), len(q.terms)+1)
+	copy(terms, q.terms)
+	return SearchQuery{terms: append(terms, term)}
+}
+
+// quoteSearchValue wraps values containing spaces, colons or parentheses in
+// double quotes. GitHub has no escape for a quote inside a value, so those are dropped.
+func quoteSearchValue(value string) string {
+	value = strings.ReplaceAll(value, `"`, "")
+	if strings.ContainsAny(value, " \t:()") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// DateRange is a created:/updated:/closed: range. A zero From or To leaves that side open.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func Since(t time.Time) DateRange  { return DateRange{From: t} }
+func Before(t time.Time) DateRange { return DateRange{To: t} }
+
+func (r DateRange) String() string {
+	const day = "2006-01-02"
+	switch {
+	case r.From.IsZero() && r.To.IsZero():
+		return "*..*"
+	case r.To.IsZero():
+		return ">=" + r.From.Format(day)
+	case r.From.IsZero():
+		return "<=" + r.To.Format(day)
+	default:
+		return r.From.Format(day) + ".." + r.To.Format(day)
+	}
+}
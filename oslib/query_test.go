@@ -0,0 +1,110 @@
+package oslib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSearchQueryString(t *testing.T) {
+	day := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		q    SearchQuery
+		want string
+	}{
+		{"empty", NewSearchQuery(), ""},
+		{"qualifiers in order", NewSearchQuery().Author("octocat").Is("pr").State("open"), "author:octocat is:pr state:open"},
+		{"quoted label", NewSearchQuery().Label("good first issue"), `label:"good first issue"`},
+		{"negated", NewSearchQuery().Without("linked", "pr"), "-linked:pr"},
+		{"since", NewSearchQuery().Updated(Since(day)), "updated:>=2025-07-01"},
+		{"before", NewSearchQuery().Closed(Before(day)), "closed:<=2025-07-01"},
+		{"range", NewSearchQuery().Merged(DateRange{From: day, To: day.AddDate(0, 1, 0)}), "merged:2025-07-01..2025-08-01"},
+		{"text", NewSearchQuery().Text("co-authored-by"), "co-authored-by"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryIsAValue(t *testing.T) {
+	base := NewSearchQuery().Author("octocat")
+	open := base.State("open")
+	closed := base.State("closed")
+	if base.String() != "author:octocat" || open.String() != "author:octocat state:open" || closed.String() != "author:octocat state:closed" {
+		t.Errorf("queries sharing a base changed each other: %q, %q, %q", base, open, closed)
+	}
+}
+
+func TestSearchQueryURL(t *testing.T) {
+	q := NewSearchQuery().Label("good first issue").Org("kubernetes")
+	for page, wantPage := range map[int]string{1: "", 3: "3"} {
+		u, err := url.Parse(q.URL(page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := u.Query()
+		if got := values.Get("q"); got != q.String() {
+			t.Errorf("URL(%d) q = %q, want %q", page, got, q.String())
+		}
+		if got := values.Get("per_page"); got != "100" {
+			t.Errorf("URL(%d) per_page = %q, want 100", page, got)
+		}
+		if got := values.Get("page"); got != wantPage {
+			t.Errorf("URL(%d) page = %q, want %q", page, got, wantPage)
+		}
+	}
+}
+
+func TestSearchIssuesFollowsPages(t *testing.T) {
+	const total = 1234
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		var items []Issue
+		for i := (page - 1) * 100; i < min(page*100, total); i++ {
+			items = append(items, Issue{Number: i, URL: fmt.Sprint(i), Repo: "https://api.github.com/repos/o/r"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"total_count": total, "items": items})
+	}))
+	defer server.Close()
+	useServer(t, server)
+
+	issues, err := searchIssues(context.Background(), NewSearchQuery().Org("o"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != searchMaxResults || requests != searchMaxResults/searchPerPage {
+		t.Errorf("got %d issues in %d requests, want %d in %d", len(issues), requests, searchMaxResults, searchMaxResults/searchPerPage)
+	}
+	if issues[0].Owner != "o" || issues[0].Repo != "r" {
+		t.Errorf("repository not split: owner %q, repo %q", issues[0].Owner, issues[0].Repo)
+	}
+}
+
+// useServer sends every GitHub request of the test to server.
+func useServer(t *testing.T, server *httptest.Server) {
+	target, _ := url.Parse(server.URL)
+	old := httpClient.Transport
+	httpClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	t.Cleanup(func() { httpClient.Transport = old })
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,189 @@
+package oslib
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Page kinds understood by every renderer.
+const (
+	PageDashboard    = "dashboard"
+	PageIssues       = "issues"
+	PageAchievements = "achievements"
+	PageKubernetes   = "kubernetes"
+	PageLabelIndex   = "labels"
+	PageTeams        = "teams"
+	PageUserChanges  = "user_changes"
+	PageCycleTime    = "cycle_time"
+	PageStale        = "stale"
+	PageLeaderboard  = "leaderboard"
+)
+
+// Page is a single report to render: Kind selects the template, Name the output file and Data the model.
+type Page struct {
+	Kind string
+	Name string
+	Data interface{}
+}
+
+// Renderer turns a report model into one output format.
+type Renderer interface {
+	Ext() string
+	Render(w io.Writer, p Page) error
+}
+
+var Formats = []string{"html", "markdown", "json", "text"}
+
+func NewRenderer(format string) (Renderer, error) {
+	switch format {
+	case "html":
+		return htmlRenderer{}, nil
+	case "markdown", "md":
+		return markdownRenderer{}, nil
+	case "json":
+		return jsonRenderer{}, nil
+	case "text", "txt":
+		return textRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Output is where generated reports go: a renderer and a directory.
+// A Dir of "-" writes every page to standard output instead.
+type Output struct {
+	Renderer Renderer
+	Dir      string
+}
+
+// Stdout reports whether pages are written to standard output.
+func (o Output) Stdout() bool {
+	return o.Dir == "-"
+}
+
+// Write renders p into Dir/<name><ext> and returns the path written.
+func (o Output) Write(p Page) (string, error) {
+	if o.Stdout() {
+		if err := o.Renderer.Render(os.Stdout, p); err != nil {
+			return "", fmt.Errorf("rendering %s: %w", p.Name, err)
+		}
+		return "stdout", nil
+	}
+
+	if err := os.MkdirAll(o.Dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(o.Dir, p.Name+o.Renderer.Ext())
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := o.Renderer.Render(f, p); err != nil {
+		return "", fmt.Errorf("rendering %s: %w", p.Name, err)
+	}
+	return path, f.Close()
+}
+
+// renderTemplate executes the template registered for p.Kind as plain text.
+func renderTemplate(w io.Writer, name string, templates map[string]string, funcs template.FuncMap, p Page) error {
+	text, err := pageTemplate(name, templates, p)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(p.Kind).Funcs(funcs).Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, p.Data)
+}
+
+func pageTemplate(name string, templates map[string]string, p Page) (string, error) {
+	text, ok := templates[p.Kind]
+	if !ok {
+		return "", fmt.Errorf("%s renderer has no template for %q pages", name, p.Kind)
+	}
+	return text, nil
+}
+
+// baseFuncs are the template helpers shared by all text-based renderers.
+// ext is the renderer's file extension, used by "link" to point at sibling pages.
+func baseFuncs(ext string) template.FuncMap {
+	return template.FuncMap{
+		"link": func(name string) string {
+			return name + ext
+		},
+		"formatDate": func(t time.Time) string {
+			return t.Format("Jan 2")
+		},
+		"formatHours":  formatHours,
+		"formatPoints": formatPoints,
+		"actionLabel": func(action string) string {
+			switch action {
+			case "created_issue_open":
+				return "Created Issue (Open)"
+			case "created_issue_closed":
+				return "Created Issue (Closed)"
+			case "opened_pr":
+				return "Opened PR"
+			case "closed_pr":
+				return "Closed PR"
+			case "merged_pr":
+				return "Merged PR"
+			case "reviewed_pr":
+				return "Reviewed PR"
+			case "commented":
+				return "Commented"
+			case "committed":
+				return "Commit"
+			default:
+				return action
+			}
+		},
+		"filterByAction": func(acts []Activity, action string) []Activity {
+			var filtered []Activity
+			for _, a := range acts {
+				if a.Action == action {
+					filtered = append(filtered, a)
+				}
+			}
+			return filtered
+		},
+		"join": strings.Join,
+		"dict": func(kv ...interface{}) map[string]interface{} {
+			m := make(map[string]interface{}, len(kv)/2)
+			for i := 0; i+1 < len(kv); i += 2 {
+				m[fmt.Sprint(kv[i])] = kv[i+1]
+			}
+			return m
+		},
+	}
+}
+
+// formatHours shows a duration in hours as minutes, hours or days, whichever reads best.
+func formatHours(h float64) string {
+	switch {
+	case h < 1:
+		return fmt.Sprintf("%.0fm", h*60)
+	case h < 48:
+		return fmt.Sprintf("%.1fh", h)
+	default:
+		return fmt.Sprintf("%.1fd", h/24)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
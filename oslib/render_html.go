@@ -0,0 +1,828 @@
+package oslib
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+type htmlRenderer struct{}
+
+func (htmlRenderer) Ext() string { return ".html" }
+
+func (htmlRenderer) Render(w io.Writer, p Page) error {
+	funcs := baseFuncs(".html")
+	funcs["badgeClass"] = func(action string) string {
+		switch action {
+		case "created_issue_open":
+			return "primary"
+		case "created_issue_closed":
+			return "info"
+		case "opened_pr":
+			return "warning"
+		case "closed_pr":
+			return "success"
+		case "reviewed_pr":
+			return "dark"
+		case "commented":
+			return "light text-dark"
+		case "committed":
+			return "secondary"
+		default:
+			return "secondary"
+		}
+	}
+	funcs["toneClass"] = func(tone string) string {
+		switch tone {
+		case "ok":
+			return "success"
+		case "bad":
+			return "danger"
+		case "wait":
+			return "warning text-dark"
+		default:
+			return "secondary"
+		}
+	}
+	funcs["escapeID"] = func(s string) string {
+		safe := strings.ReplaceAll(s, "@", "_")
+		safe = strings.ReplaceAll(safe, ".", "_")
+		safe = strings.ReplaceAll(safe, "/", "_")
+		return safe
+	}
+
+	// Define the color palette (rotates if more repos than colors)
+	colors := []string{
+		"primary", "success", "info", "warning", "danger", "secondary", "dark",
+	}
+	// Build a map: repoName -> assigned color index
+	repoColorMap := make(map[string]string)
+	colorIndex := 0
+	funcs["assignColor"] = func(repo string) string {
+		if color, exists := repoColorMap[repo]; exists {
+			return color
+		}
+		color := colors[colorIndex%len(colors)]
+		repoColorMap[repo] = color
+		colorIndex++
+		return color
+	}
+
+	// html/template escapes titles, names and labels from GitHub, which serve publishes live.
+	text, err := pageTemplate("html", htmlTemplates, p)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(p.Kind).Funcs(funcs).Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, p.Data)
+}
+
+var htmlTemplates = map[string]string{
+	PageIssues: `
+    <!DOCTYPE html>
+    <html>
+    <head>
+        <title>{{ .Title }}</title>
+        <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+        {{ if .Feed }}
+        <link rel="alternate" type="application/atom+xml" title="Atom feed" href="{{ .Feed }}.atom">
+        <link rel="alternate" type="application/feed+json" title="JSON feed" href="{{ .Feed }}.feed.json">
+        {{ end }}
+    </head>
+    <body class="container mt-5">
+        <h1 class="mb-4">{{ .Title }}</h1>
+        {{ if .Feed }}
+        <p>Subscribe: <a href="{{ .Feed }}.atom">Atom</a> · <a href="{{ .Feed }}.feed.json">JSON Feed</a></p>
+        {{ end }}
+        {{ if .Issues }}
+        <table class="table table-striped">
+            <thead>
+                <tr>
+                    <th>Title</th>
+                    <th>Repository</th>
+                    <th>URL</th>
+                    <th>Created At</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{ range .Issues }}
+                <tr>
+                    <td>{{ .Title }}</td>
+                    <td>{{ .Repo }}</td>
+                    <td><a href="{{ .URL }}" target="_blank">{{ .URL }}</a></td>
+                    <td>{{ .CreatedAt }}</td>
+                </tr>
+                {{ end }}
+            </tbody>
+        </table>
+        {{ else }}
+        <p>No issues found</p>
+        {{ end }}
+        {{ if .LikelyTaken }}
+        <h2 class="mt-4">Likely Taken</h2>
+        <p class="text-muted">Someone asked to work on these in a comment.</p>
+        <table class="table table-striped">
+            <thead>
+                <tr>
+                    <th>Title</th>
+                    <th>Repository</th>
+                    <th>Claimed By</th>
+                    <th>Claimed At</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{ range .LikelyTaken }}
+                <tr>
+                    <td><a href="{{ .URL }}" target="_blank">{{ .Title }}</a></td>
+                    <td>{{ .Repo }}</td>
+                    <td>{{ .Claim.By }}</td>
+                    <td><a href="{{ .Claim.URL }}" target="_blank">{{ .Claim.At }}</a></td>
+                </tr>
+                {{ end }}
+            </tbody>
+        </table>
+        {{ end }}
+    </body>
+    </html>
+    `,
+
+	PageLabelIndex: `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Issue Labels</title>
+	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+</head>
+<body class="container mt-5">
+	<h1 class="mb-4">Issue Labels</h1>
+	{{ if .Labels }}
+	<table class="table table-striped">
+		<thead>
+			<tr><th>Label</th><th>Open Issues</th><th>Likely Taken</th>{{ if .Feeds }}<th>Feeds</th>{{ end }}</tr>
+		</thead>
+		<tbody>
+			{{ $feeds := .Feeds }}
+			{{ range .Labels }}
+			<tr>
+				<td><a href="{{ link .Page }}">{{ .Title }}</a></td>
+				<td>{{ .Count }}</td>
+				<td>{{ .Taken }}</td>
+				{{ if $feeds }}<td><a href="{{ .Page }}.atom">Atom</a> · <a href="{{ .Page }}.feed.json">JSON Feed</a></td>{{ end }}
+			</tr>
+			{{ end }}
+		</tbody>
+	</table>
+	{{ else }}
+	<p>No labels configured</p>
+	{{ end }}
+</body>
+</html>
+`,
+
+	PageDashboard: `
+	<!DOCTYPE html>
+	<html>
+	<head>
+		<title>{{ .Title }}</title>
+		<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+		<script src="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/js/bootstrap.bundle.min.js"></script>
+		<style>
+			h3 { font-size: 1.25rem; font-weight: bold; background-color: #f8f9fa; padding: 0.5rem; border-radius: 0.25rem; }
+			.table td, .table th { width: auto; text-align: left; }
+		</style>
+	</head>
+	<body class="container mt-5">
+		<h1 class="mb-4">{{ .Title }}</h1>
+		{{ with .Classes }}
+		<ul class="nav nav-pills mb-4">
+			{{ range . }}<li class="nav-item"><a class="nav-link{{ if .Current }} active{{ end }}" href="{{ link .Page }}">{{ .Class }}</a></li>{{ end }}
+		</ul>
+		{{ end }}
+		{{ range $si, $section := .Sections }}
+		{{ if $section.Team }}
+		<h2 class="mt-4">{{ if $section.Page }}<a href="{{ link $section.Page }}">{{ $section.Team }}</a>{{ else }}{{ $section.Team }}{{ end }}</h2>
+		{{ if $section.Leads }}<p class="text-muted">Led by {{ join $section.Leads ", " }}</p>{{ end }}
+		{{ end }}
+		<div class="accordion mb-4" id="usersAccordion-{{ $si }}">
+			{{ range $data := $section.Users }}
+			{{ $user := $data.User }}
+			{{ $id := printf "%d-%s" $si $user }}
+			<div class="accordion-item">
+				<h2 class="accordion-header" id="heading-{{ $id }}">
+					<button class="accordion-button collapsed" type="button" data-bs-toggle="collapse" data-bs-target="#collapse-{{ $id }}" aria-expanded="false" aria-controls="collapse-{{ $id }}">
+						{{ if $data.Avatar }}<img src="{{ $data.Avatar }}" alt="" width="32" height="32" class="rounded-circle me-2">{{ end }}
+						<span style="font-size: 1.5rem; font-weight: bold;">{{ $data.Name }}</span>
+						{{ if $data.Lead }}<span class="badge bg-secondary ms-2">Lead</span>{{ end }}
+						{{ if $data.StaleCount }}<span class="badge bg-warning text-dark ms-2">{{ $data.StaleCount }} stale</span>{{ end }}
+						{{ with $data.WaitingOnMe }}<span class="badge bg-info text-dark ms-2">{{ len . }} waiting</span>{{ end }}
+						{{ range $data.Classes }}<span class="badge bg-light text-dark border ms-2">{{ .Class }}: {{ .Count }}</span>{{ end }}
+					</button>
+				</h2>
+				<div id="collapse-{{ $id }}" class="accordion-collapse collapse" aria-labelledby="heading-{{ $id }}" data-bs-parent="#usersAccordion-{{ $si }}">
+					<div class="accordion-body">
+						{{ if $.ShowWaiting }}
+						<h3 style="background-color: #cff4fc;">Waiting on Me</h3>
+						{{ if $data.WaitingOnMe }}
+						<table class="table table-striped">
+							<thead>
+								<tr><th>Title</th><th>Repository</th><th>Why</th><th>Last Action</th></tr>
+							</thead>
+							<tbody>
+								{{ range $item := $data.WaitingOnMe }}
+								<tr>
+									<td><a href="{{ $item.Issue.URL }}" target="_blank">{{ $item.Issue.Title }}</a></td>
+									<td>{{ $item.Issue.Repo }}</td>
+									<td>{{ range $item.Reasons }}<span class="badge bg-info text-dark me-1">{{ . }}</span>{{ end }}</td>
+									<td>{{ if $item.LastActor }}{{ $item.LastActor }} at {{ $item.LastActionAt }}{{ else }}opened {{ $item.Issue.CreatedAt }}{{ end }}</td>
+								</tr>
+								{{ end }}
+							</tbody>
+						</table>
+						{{ else }}<p>Nothing waiting</p>{{ end }}
+						{{ end }}
+						<h3 style="background-color: #d1e7dd;">Assigned Issues</h3>
+						{{ if $data.AssignedIssues }}
+						<table class="table table-striped">
+							<thead>
+								<tr><th>Title</th><th>Repository</th><th>URL</th><th>Updated At</th></tr>
+							</thead>
+							<tbody>
+								{{ range $issue := $data.AssignedIssues }}
+								{{ $stale := index $data.Stale $issue.URL }}
+								<tr{{ if $stale }} class="table-warning"{{ end }}>
+									<td>{{ $issue.Title }}{{ if $stale }} <span class="badge bg-warning text-dark" title="{{ $stale }}">Stale</span>{{ end }}</td>
+									<td>{{ $issue.Repo }}</td>
+									<td><a href="{{ $issue.URL }}" target="_blank">{{ $issue.URL }}</a></td>
+									<td>{{ $issue.UpdatedAt }}</td>
+								</tr>
+								{{ end }}
+							</tbody>
+						</table>
+						{{ else }}<p>No assigned issues</p>{{ end }}
+
+						<h3 style="background-color: #ffeeba;">Created Issues</h3>
+						{{ if $data.CreatedIssues }}
+						<table class="table table-striped">
+							<thead>
+								<tr><th>Title</th><th>Repository</th><th>URL</th><th>Updated At</th></tr>
+							</thead>
+							<tbody>
+								{{ range $issue := $data.CreatedIssues }}
+								<tr>
+									<td>{{ $issue.Title }}</td>
+									<td>{{ $issue.Repo }}</td>
+									<td><a href="{{ $issue.URL }}" target="_blank">{{ $issue.URL }}</a></td>
+									<td>{{ $issue.UpdatedAt }}</td>
+								</tr>
+								{{ end }}
+							</tbody>
+						</table>
+						{{ else }}<p>No created issues</p>{{ end }}
+
+						<h3 style="background-color: #f8d7da;">Open PRs</h3>
+						{{ if $data.OpenPRs }}
+						<table class="table table-striped">
+							<thead>
+								<tr><th>Title</th><th>Repository</th><th>URL</th><th>Updated At</th>{{ if $data.PRStatus }}<th>Status</th>{{ end }}</tr>
+							</thead>
+							<tbody>
+								{{ range $issue := $data.OpenPRs }}
+								{{ $stale := index $data.Stale $issue.URL }}
+								<tr{{ if $stale }} class="table-warning"{{ end }}>
+									<td>{{ $issue.Title }}{{ if $stale }} <span class="badge bg-warning text-dark" title="{{ $stale }}">Stale</span>{{ end }}</td>
+									<td>{{ $issue.Repo }}</td>
+									<td><a href="{{ $issue.URL }}" target="_blank">{{ $issue.URL }}</a></td>
+									<td>{{ $issue.UpdatedAt }}</td>
+									{{ if $data.PRStatus }}<td>{{ range (index $data.PRStatus $issue.URL).Badges }}<span class="badge bg-{{ toneClass .Tone }} me-1">{{ .Label }}</span>{{ end }}</td>{{ end }}
+								</tr>
+								{{ end }}
+							</tbody>
+						</table>
+						{{ else }}<p>No open PRs</p>{{ end }}
+						<h3 style="background-color: #f8d7da;">Closed PRs (past 1 year)</h3>
+						{{ if $data.ClosedPRs }}
+						<table class="table table-striped">
+							<thead>
+								<tr><th>Title</th><th>Repository</th><th>URL</th><th>Updated At</th></tr>
+							</thead>
+							<tbody>
+								{{ range $issue := $data.ClosedPRs }}
+								<tr>
+									<td>{{ $issue.Title }}</td>
+									<td>{{ $issue.Repo }}</td>
+									<td><a href="{{ $issue.URL }}" target="_blank">{{ $issue.URL }}</a></td>
+									<td>{{ $issue.UpdatedAt }}</td>
+								</tr>
+								{{ end }}
+							</tbody>
+						</table>
+						{{ else }}<p>No closed PRs</p>{{ end }}
+						{{ if $.ShowReviews }}
+						<h3 style="background-color: #d6d8d9;">Reviews (past 1 year)</h3>
+						{{ if $data.Reviews }}
+						<table class="table table-striped">
+							<thead>
+								<tr><th>Title</th><th>Repository</th><th>Review</th><th>Submitted At</th></tr>
+							</thead>
+							<tbody>
+								{{ range $review := $data.Reviews }}
+								<tr>
+									<td><a href="{{ $review.PR.URL }}" target="_blank">{{ $review.PR.Title }}</a></td>
+									<td>{{ $review.PR.Repo }}</td>
+									<td>{{ $review.StateLabel }}</td>
+									<td>{{ $review.SubmittedAt }}</td>
+								</tr>
+								{{ end }}
+							</tbody>
+						</table>
+						{{ else }}<p>No reviews</p>{{ end }}
+						{{ end }}
+						{{ if $.ShowCommits }}
+						<h3 style="background-color: #e2e3e5;">Commits (past 1 year)</h3>
+						{{ if $data.Commits }}
+						<table class="table table-striped">
+							<thead>
+								<tr><th>Commit</th><th>Message</th><th>Repository</th><th>Committed At</th></tr>
+							</thead>
+							<tbody>
+								{{ range $commit := $data.Commits }}
+								<tr>
+									<td><a href="{{ $commit.URL }}" target="_blank"><code>{{ $commit.ShortSHA }}</code></a>{{ if $commit.CoAuthored }} <span class="badge bg-secondary">co-author</span>{{ end }}</td>
+									<td>{{ $commit.Message }}</td>
+									<td>{{ $commit.Repo }}</td>
+									<td>{{ $commit.Date }}</td>
+								</tr>
+								{{ end }}
+							</tbody>
+						</table>
+						{{ else }}<p>No commits</p>{{ end }}
+						{{ end }}
+					</div>
+				</div>
+			</div>
+			{{ end }}
+		</div>
+		{{ end }}
+
+		</tbody>
+	</table>
+</div>
+	</body>
+	</html>
+	`,
+
+	PageAchievements: `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>{{ .Title }}</title>
+	<meta charset="utf-8">
+	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+	<script src="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/js/bootstrap.bundle.min.js"></script>
+</head>
+<body class="container mt-5">
+	<h1 class="mb-4">{{ .Title }} by Month</h1>
+	{{ with .Classes }}
+	<ul class="nav nav-pills mb-4">
+		{{ range . }}<li class="nav-item"><a class="nav-link{{ if .Current }} active{{ end }}" href="{{ link .Page }}">{{ .Class }}</a></li>{{ end }}
+	</ul>
+	{{ end }}
+
+	{{ range .Months }}
+		{{ $month := .Month }}
+		<h2 class="mt-4">{{ $month }}</h2>
+
+		{{ range $si, $section := .Sections }}
+		{{ if $section.Team }}
+		<h3 class="mt-3 h5">{{ if $section.Page }}<a href="{{ link $section.Page }}">{{ $section.Team }}</a>{{ else }}{{ $section.Team }}{{ end }}</h3>
+		{{ end }}
+
+		{{ range $section.Users }}
+			{{ $user := .User }}
+			{{ $name := .Name }}
+			{{ $avatar := .Avatar }}
+			{{ $activities := .Activities }}
+			{{ $classes := .Classes }}
+			<div class="card mb-2">
+				<div class="card-header">
+					<h5 class="mb-0">
+						<button class="btn btn-link text-decoration-none" data-bs-toggle="collapse" data-bs-target="#collapse-{{ $month }}-{{ $si }}-{{ $user | escapeID }}" aria-expanded="false" aria-controls="collapse-{{ $month }}-{{ $si }}-{{ $user | escapeID }}">
+							{{ if $avatar }}<img src="{{ $avatar }}" alt="" width="24" height="24" class="rounded-circle me-1">{{ end }}{{ $name }}
+						</button>
+					</h5>
+					<div class="mt-2">
+						<span class="badge bg-primary">Open Issues: {{ len (filterByAction $activities "created_issue_open") }}</span>
+						<span class="badge bg-info text-dark">Closed Issues: {{ len (filterByAction $activities "created_issue_closed") }}</span>
+						<span class="badge bg-warning text-dark">Opened PRs: {{ len (filterByAction $activities "opened_pr") }}</span>
+						<span class="badge bg-success">Closed PRs: {{ len (filterByAction $activities "closed_pr") }}</span>
+						<span class="badge bg-dark">Reviews: {{ len (filterByAction $activities "reviewed_pr") }}</span>
+						<span class="badge bg-light text-dark">Comments: {{ len (filterByAction $activities "commented") }}</span>
+						<span class="badge bg-secondary">Commits: {{ len (filterByAction $activities "committed") }}</span>
+						{{ range $classes }}<span class="badge bg-light text-dark border">{{ .Class }}: {{ .Count }}</span>{{ end }}
+					</div>
+				</div>
+				<div id="collapse-{{ $month }}-{{ $si }}-{{ $user | escapeID }}" class="collapse">
+					<ul class="list-group list-group-flush">
+						{{ range $a := $activities }}
+						<li class="list-group-item">
+							<span class="badge bg-{{ badgeClass $a.Action }}">{{ actionLabel $a.Action }}</span>
+							<a href="{{ $a.URL }}" target="_blank">{{ $a.Title }}</a>{{ if $a.Detail }} ({{ $a.Detail }}){{ end }}
+							<span class="text-muted">in {{ $a.Repo }} on {{ formatDate $a.Timestamp }}</span>
+							{{ if $a.Class }}<span class="badge bg-light text-dark border">{{ $a.Class }}</span>{{ end }}
+						</li>
+						{{ end }}
+					</ul>
+				</div>
+			</div>
+		{{ end }}
+		{{ end }}
+
+	{{ end }}
+</body>
+</html>
+`,
+
+	PageCycleTime: `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>PR Cycle Time</title>
+	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+</head>
+<body class="container mt-5">
+	<h1 class="mb-4">PR Cycle Time</h1>
+	<div class="mb-3">
+		<a href="user_dashboard.html" class="btn btn-secondary">← Back to Dashboard</a>
+	</div>
+	<p class="text-muted">Median / 90th percentile over the open PRs and the PRs closed in the past year. Times run from when the PR was opened; review rounds only count reviewed PRs.</p>
+	{{ template "groups" dict "Title" "By User" "Groups" .Users "Total" .All }}
+	{{ template "groups" dict "Title" "By Repository" "Groups" .Repos }}
+	{{ template "groups" dict "Title" "By Month Opened" "Groups" .Months }}
+
+	<h2 class="mt-4">Pull Requests</h2>
+	<table class="table table-striped">
+		<thead>
+			<tr><th>Title</th><th>User</th><th>Repository</th><th>Opened</th><th>First Review</th><th>Merge</th><th>Review Rounds</th></tr>
+		</thead>
+		<tbody>
+			{{ range .PRs }}
+			<tr>
+				<td><a href="{{ .URL }}" target="_blank">{{ .Title }}</a></td>
+				<td>{{ .Name }}</td>
+				<td>{{ .Repo }}</td>
+				<td>{{ .CreatedAt }}</td>
+				<td>{{ if .Reviewed }}{{ formatHours .FirstReviewHours }}{{ else }}-{{ end }}</td>
+				<td>{{ if .Merged }}{{ formatHours .MergeHours }}{{ else }}-{{ end }}</td>
+				<td>{{ if .Reviewed }}{{ .Rounds }}{{ else }}-{{ end }}</td>
+			</tr>
+			{{ end }}
+		</tbody>
+	</table>
+</body>
+</html>
+{{- define "groups" }}
+	<h2 class="mt-4">{{ .Title }}</h2>
+	<table class="table table-striped">
+		<thead>
+			<tr><th></th><th>PRs</th><th>Time to First Review</th><th>Time to Merge</th><th>Review Rounds</th></tr>
+		</thead>
+		<tbody>
+			{{ range .Groups }}
+			<tr>
+				<td>{{ .Name }}</td>
+				<td>{{ .PRs }}</td>
+				<td>{{ template "hours" .FirstReview }}</td>
+				<td>{{ template "hours" .Merge }}</td>
+				<td>{{ template "rounds" .Rounds }}</td>
+			</tr>
+			{{ end }}
+		</tbody>
+		{{ with .Total }}
+		<tfoot class="fw-bold">
+			<tr>
+				<td>{{ .Name }}</td>
+				<td>{{ .PRs }}</td>
+				<td>{{ template "hours" .FirstReview }}</td>
+				<td>{{ template "hours" .Merge }}</td>
+				<td>{{ template "rounds" .Rounds }}</td>
+			</tr>
+		</tfoot>
+		{{ end }}
+	</table>
+{{- end }}
+{{- define "hours" }}{{ if .Count }}{{ formatHours .Median }} / {{ formatHours .P90 }}{{ else }}-{{ end }}{{ end }}
+{{- define "rounds" }}{{ if .Count }}{{ printf "%.0f" .Median }} / {{ printf "%.0f" .P90 }}{{ else }}-{{ end }}{{ end }}
+`,
+
+	PageStale: `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Needs Attention</title>
+	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+</head>
+<body class="container mt-5">
+	<h1 class="mb-4">Needs Attention</h1>
+	<div class="mb-3">
+		<a href="user_dashboard.html" class="btn btn-secondary">← Back to Dashboard</a>
+	</div>
+	<p class="text-muted">Open PRs and assigned issues{{ with .Thresholds.DaysSinceUpdate }} not updated for {{ . }} days{{ end }}{{ with .Thresholds.DaysSinceComment }}{{ if $.Thresholds.DaysSinceUpdate }}, or{{ end }} without a reply from anyone but their author for {{ . }} days{{ end }}, longest untouched first.</p>
+	{{ if .Items }}
+	<table class="table table-striped">
+		<thead>
+			<tr><th>Title</th><th>Kind</th><th>User</th><th>Repository</th><th>Days Since Update</th><th>Why</th></tr>
+		</thead>
+		<tbody>
+			{{ range .Items }}
+			<tr>
+				<td><a href="{{ .Issue.URL }}" target="_blank">{{ .Issue.Title }}</a></td>
+				<td>{{ .Kind }}</td>
+				<td>{{ .Name }}</td>
+				<td>{{ .Issue.Repo }}</td>
+				<td>{{ .AgeDays }}</td>
+				<td>{{ .Reason }}</td>
+			</tr>
+			{{ end }}
+		</tbody>
+	</table>
+	{{ else }}<p>Nothing needs attention.</p>{{ end }}
+</body>
+</html>
+`,
+
+	PageLeaderboard: `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Leaderboard</title>
+	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+</head>
+<body class="container mt-5">
+	<h1 class="mb-4">Leaderboard</h1>
+	<div class="mb-3">
+		<a href="user_dashboard.html" class="btn btn-secondary">← Back to Dashboard</a>
+	</div>
+	<p class="text-muted">Each activity scores the weight of its kind, multiplied for its repo tier, labels and PR size.</p>
+	<h2 class="mt-4">Last {{ .RollingDays }} Days</h2>
+	{{ if .Rolling }}
+	{{ template "entries" .Rolling }}
+	<h3 class="mt-4">How the Scores Add Up</h3>
+	{{ range .Rolling }}
+	<details class="mb-3">
+		<summary>{{ .Name }}: {{ formatPoints .Points }} points</summary>
+		<table class="table table-sm">
+			<thead>
+				<tr><th>Title</th><th>Kind</th><th>Repository</th><th>Date</th><th>Points</th><th>Score</th></tr>
+			</thead>
+			<tbody>
+				{{ range .Breakdown }}
+				<tr>
+					<td><a href="{{ .URL }}" target="_blank">{{ .Title }}</a></td>
+					<td>{{ actionLabel .Kind }}</td>
+					<td>{{ .Repo }}</td>
+					<td>{{ .Timestamp.Format "2006-01-02" }}</td>
+					<td>{{ formatPoints .Points }}</td>
+					<td><small class="text-muted">{{ .Explain }}</small></td>
+				</tr>
+				{{ end }}
+			</tbody>
+		</table>
+	</details>
+	{{ end }}
+	{{ else }}<p>No activity in the last {{ .RollingDays }} days.</p>{{ end }}
+	<h2 class="mt-4">By Month</h2>
+	{{ range .Months }}
+	<h3>{{ .Month }}</h3>
+	{{ template "entries" .Entries }}
+	{{ else }}<p>No activity.</p>{{ end }}
+	<h2 class="mt-4">Weights</h2>
+	<p>{{ range $kind, $weight := .Weights }}<span class="badge bg-secondary me-1">{{ actionLabel $kind }}: {{ formatPoints $weight }}</span>{{ end }}</p>
+</body>
+</html>
+{{ define "entries" }}
+	<table class="table table-striped">
+		<thead>
+			<tr><th>Rank</th><th>User</th><th>Points</th><th>Made Of</th></tr>
+		</thead>
+		<tbody>
+			{{ range . }}
+			<tr>
+				<td>{{ .Rank }}</td>
+				<td>{{ if .Avatar }}<img src="{{ .Avatar }}" alt="" width="24" height="24" class="rounded-circle me-2">{{ end }}{{ .Name }}</td>
+				<td>{{ formatPoints .Points }}</td>
+				<td>{{ range $i, $k := .Kinds }}{{ if $i }}, {{ end }}{{ $k.Count }} × {{ actionLabel $k.Kind }} ({{ formatPoints $k.Points }}){{ end }}</td>
+			</tr>
+			{{ end }}
+		</tbody>
+	</table>
+{{ end }}
+`,
+
+	PageTeams: `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Teams</title>
+	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+</head>
+<body class="container mt-5">
+	<h1 class="mb-4">Teams</h1>
+	<div class="mb-3">
+		<a href="user_dashboard.html" class="btn btn-secondary">← Back to Dashboard</a>
+	</div>
+	<table class="table table-striped">
+		<thead>
+			<tr>
+				<th>Team</th>
+				<th>Leads</th>
+				<th>Members</th>
+				<th>Assigned Issues</th>
+				<th>Created Issues</th>
+				<th>Open PRs</th>
+				<th>Closed PRs (past 1 year)</th>
+				<th>Merged PRs</th>
+				<th>Activity in {{ .Month }}</th>
+			</tr>
+		</thead>
+		<tbody>
+			{{ range .Teams }}
+			<tr>
+				<td><a href="{{ link .Page }}">{{ .Team }}</a></td>
+				<td>{{ join .Leads ", " }}</td>
+				<td>{{ .Members }}</td>
+				<td>{{ .AssignedIssues }}</td>
+				<td>{{ .CreatedIssues }}</td>
+				<td>{{ .OpenPRs }}</td>
+				<td>{{ .ClosedPRs }}</td>
+				<td>{{ .MergedPRs }}</td>
+				<td>{{ .Activity }}</td>
+			</tr>
+			{{ end }}
+		</tbody>
+		<tfoot class="fw-bold">
+			{{ with .Org }}
+			<tr>
+				<td>{{ .Team }}</td>
+				<td></td>
+				<td>{{ .Members }}</td>
+				<td>{{ .AssignedIssues }}</td>
+				<td>{{ .CreatedIssues }}</td>
+				<td>{{ .OpenPRs }}</td>
+				<td>{{ .ClosedPRs }}</td>
+				<td>{{ .MergedPRs }}</td>
+				<td>{{ .Activity }}</td>
+			</tr>
+			{{ end }}
+		</tfoot>
+	</table>
+	<p class="text-muted">Issues and PRs shared by several members are counted once per team and once overall.</p>
+</body>
+</html>
+`,
+
+	PageUserChanges: `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Tracked User Changes</title>
+	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+</head>
+<body class="container mt-5">
+	<h1 class="mb-4">Tracked User Changes</h1>
+	<div class="mb-3">
+		<a href="user_dashboard.html" class="btn btn-secondary">← Back to Dashboard</a>
+	</div>
+	{{ if .Since.IsZero }}
+	<p>This is the first run; {{ .Users }} users are tracked. Changes will be listed from the next run on.</p>
+	{{ else }}
+	<p>{{ .Users }} users are tracked. Compared with the previous run on {{ .Since.Format "2006-01-02 15:04 MST" }}:</p>
+	{{ if or .Added .Removed }}
+	<ul>
+		{{ range .Added }}<li><span class="badge bg-success">Added</span> <a href="https://github.com/{{ . }}" target="_blank">{{ . }}</a></li>{{ end }}
+		{{ range .Removed }}<li><span class="badge bg-danger">Removed</span> <a href="https://github.com/{{ . }}" target="_blank">{{ . }}</a></li>{{ end }}
+	</ul>
+	{{ else }}
+	<p>No users were added or removed.</p>
+	{{ end }}
+	{{ end }}
+	{{ if .History }}
+	<h2 class="mt-4">History</h2>
+	<table class="table table-striped">
+		<thead>
+			<tr><th>Date</th><th>Added</th><th>Removed</th></tr>
+		</thead>
+		<tbody>
+			{{ range .History }}
+			<tr>
+				<td>{{ .At.Format "2006-01-02" }}</td>
+				<td>{{ join .Added ", " }}</td>
+				<td>{{ join .Removed ", " }}</td>
+			</tr>
+			{{ end }}
+		</tbody>
+	</table>
+	{{ end }}
+</body>
+</html>
+`,
+
+	PageKubernetes: `
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Kubernetes PR Contributions</title>
+	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+	<style>
+		body { font-size: 0.95rem; background-color: #f8f9fa; }
+		table td, table th { vertical-align: top; }
+		.pr-btn {
+			font-size: 0.75rem;
+			padding: 2px 6px;
+			border-radius: 0.4rem;
+			margin: 2px;
+			text-decoration: none;
+		}
+		.pr-btn:hover { opacity: 0.85; text-decoration: underline; }
+
+		.username-container {
+			position: relative;
+			display: inline-block;
+		}
+		.contributor-popup {
+			display: none;
+			position: absolute;
+			top: 25px;
+			left: 0;
+			z-index: 100;
+			width: 350px;
+			height: 480px;
+			border: 2px solid #ccc;
+			border-radius: 0.5rem;
+			overflow: hidden;
+			box-shadow: 0 4px 10px rgba(0,0,0,0.1);
+			background-color: white;
+		}
+		.username-container:hover .contributor-popup {
+			display: block;
+		}
+		.username-link {
+			color: #0d6efd;
+			text-decoration: none;
+			font-weight: 600;
+			cursor: pointer;
+		}
+		.username-link:hover {
+			text-decoration: underline;
+			color: #084298;
+		}
+	</style>
+</head>
+<body class="container mt-5">
+	<h1 class="mb-4">Kubernetes PR Contributions</h1>
+	<div class="mb-3">
+		<a href="user_dashboard.html" class="btn btn-secondary">← Back to Dashboard</a>
+	</div>
+
+	<table class="table table-bordered table-sm align-middle">
+		<thead class="table-light">
+			<tr>
+				<th style="width: 20%;">User</th>
+				<th>Pull Requests</th>
+			</tr>
+		</thead>
+		<tbody>
+			{{ range .Users }}
+			{{ $user := .User }}
+			{{ $prs := .PRs }}
+			<tr>
+				<td>
+					<div class="username-container">
+						<span class="username-link">{{ .Name }}</span>
+						<div class="contributor-popup">
+							<iframe src="https://contribcard.clotributor.dev/{{ $user }}"
+							        width="100%" height="100%" frameborder="0"></iframe>
+						</div>
+					</div>
+				</td>
+				<td>
+					{{ if $prs }}
+						{{ range $pr := $prs }}
+							<a href="{{ $pr.URL }}" target="_blank"
+							   class="btn btn-{{ assignColor $pr.Repo }} pr-btn"
+							   title="{{ $pr.Title }}">{{ $pr.Repo }}</a>
+						{{ end }}
+					{{ else }}
+						<em>No PRs</em>
+					{{ end }}
+				</td>
+			</tr>
+			{{ end }}
+		</tbody>
+	</table>
+</body>
+</html>
+`,
+}
@@ -0,0 +1,17 @@
+package oslib
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonRenderer writes the report model as indented JSON so other tools can consume it.
+type jsonRenderer struct{}
+
+func (jsonRenderer) Ext() string { return ".json" }
+
+func (jsonRenderer) Render(w io.Writer, p Page) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p.Data)
+}
@@ -0,0 +1,223 @@
+package oslib
+
+import (
+	"io"
+	"strings"
+)
+
+// markdownText escapes GitHub text so it can neither break a table row nor
+// close or open a link it is placed in.
+var markdownText = strings.NewReplacer(`\`, `\\`, "|", `\|`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "\n", " ")
+
+// markdownURL percent-encodes what would end a link target or a table cell early.
+var markdownURL = strings.NewReplacer("(", "%28", ")", "%29", " ", "%20", "|", "%7C")
+
+// markdownRenderer writes GitHub-flavoured Markdown that can be pasted into an issue or wiki page.
+type markdownRenderer struct{}
+
+func (markdownRenderer) Ext() string { return ".md" }
+
+func (markdownRenderer) Render(w io.Writer, p Page) error {
+	funcs := baseFuncs(".md")
+	funcs["md"] = markdownText.Replace
+	funcs["mdURL"] = markdownURL.Replace
+	return renderTemplate(w, "markdown", markdownTemplates, funcs, p)
+}
+
+var markdownTemplates = map[string]string{
+	PageIssues: `# {{ .Title }}
+{{ if .Issues }}
+| Title | Repository | Created At |
+| --- | --- | --- |
+{{ range .Issues }}| [{{ md .Title }}]({{ mdURL .URL }}) | {{ .Repo }} | {{ .CreatedAt }} |
+{{ end }}{{ else }}
+No issues found
+{{ end }}{{ if .LikelyTaken }}
+## Likely Taken
+
+Someone asked to work on these in a comment.
+
+| Title | Repository | Claimed By | Claimed At |
+| --- | --- | --- | --- |
+{{ range .LikelyTaken }}| [{{ md .Title }}]({{ mdURL .URL }}) | {{ .Repo }} | {{ md .Claim.By }} | [{{ .Claim.At }}]({{ mdURL .Claim.URL }}) |
+{{ end }}{{ end }}`,
+
+	PageLabelIndex: `# Issue Labels
+
+| Label | Open Issues | Likely Taken |
+| --- | --- | --- |
+{{ range .Labels }}| [{{ md .Title }}]({{ link .Page }}) | {{ .Count }} | {{ .Taken }} |
+{{ end }}`,
+
+	PageDashboard: `# {{ .Title }}
+{{ with .Classes }}
+Work: {{ range $i, $c := . }}{{ if $i }} · {{ end }}{{ if $c.Current }}**{{ $c.Class }}**{{ else }}[{{ md $c.Class }}]({{ link $c.Page }}){{ end }}{{ end }}
+{{ end }}{{ range .Sections }}{{ $h := "##" }}{{ if .Team }}{{ $h = "###" }}
+## {{ if .Page }}[{{ md .Team }}]({{ link .Page }}){{ else }}{{ md .Team }}{{ end }}
+{{ if .Leads }}
+Led by {{ join .Leads ", " }}
+{{ end }}{{ end }}{{ range .Users }}
+{{ $h }} {{ md .Name }}{{ if .Lead }} (lead){{ end }}{{ if .StaleCount }} ({{ .StaleCount }} stale){{ end }}{{ with .Classes }} — {{ range $i, $c := . }}{{ if $i }}, {{ end }}{{ $c.Class }} {{ $c.Count }}{{ end }}{{ end }}
+
+{{ if $.ShowWaiting }}{{ $h }}# Waiting on Me
+{{ if .WaitingOnMe }}
+| Title | Repository | Why | Last Action |
+| --- | --- | --- | --- |
+{{ range .WaitingOnMe }}| [{{ md .Issue.Title }}]({{ mdURL .Issue.URL }}) | {{ .Issue.Repo }} | {{ join .Reasons ", " }} | {{ if .LastActor }}{{ md .LastActor }} at {{ .LastActionAt }}{{ else }}opened {{ .Issue.CreatedAt }}{{ end }} |
+{{ end }}{{ else }}
+None
+{{ end }}
+{{ end }}{{ template "bucket" dict "Level" $h "Title" "Assigned Issues" "Items" .AssignedIssues "Stale" .Stale "Status" nil }}
+{{ template "bucket" dict "Level" $h "Title" "Created Issues" "Items" .CreatedIssues "Stale" nil "Status" nil }}
+{{ template "bucket" dict "Level" $h "Title" "Open PRs" "Items" .OpenPRs "Stale" .Stale "Status" .PRStatus }}
+{{ template "bucket" dict "Level" $h "Title" "Closed PRs (past 1 year)" "Items" .ClosedPRs "Stale" nil "Status" nil }}
+{{ if $.ShowReviews }}{{ $h }}# Reviews (past 1 year)
+{{ if .Reviews }}
+| Title | Repository | Review | Submitted At |
+| --- | --- | --- | --- |
+{{ range .Reviews }}| [{{ md .PR.Title }}]({{ mdURL .PR.URL }}) | {{ .PR.Repo }} | {{ .StateLabel }} | {{ .SubmittedAt }} |
+{{ end }}{{ else }}
+None
+{{ end }}{{ end }}{{ if $.ShowCommits }}
+{{ $h }}# Commits (past 1 year)
+{{ if .Commits }}
+| Commit | Message | Repository | Committed At |
+| --- | --- | --- | --- |
+{{ range .Commits }}| [{{ .ShortSHA }}]({{ mdURL .URL }}){{ if .CoAuthored }} (co-author){{ end }} | {{ md .Message }} | {{ .Repo }} | {{ .Date }} |
+{{ end }}{{ else }}
+None
+{{ end }}{{ end }}
+{{ end }}{{ end }}
+{{- define "bucket" -}}
+{{ .Level }}# {{ .Title }}
+{{ if .Items }}
+| Title | Repository | Updated At |
+| --- | --- | --- |
+{{ range $item := .Items }}| [{{ md .Title }}]({{ mdURL .URL }}){{ if $.Stale }}{{ with index $.Stale $item.URL }} **(stale: {{ . }})**{{ end }}{{ end }}{{ if $.Status }}{{ range $i, $b := (index $.Status $item.URL).Badges }}{{ if $i }}, {{ else }} _({{ end }}{{ $b.Label }}{{ end }}{{ with (index $.Status $item.URL).Badges }})_{{ end }}{{ end }} | {{ .Repo }} | {{ .UpdatedAt }} |
+{{ end }}{{ else }}
+None
+{{ end }}{{ end }}`,
+
+	PageAchievements: `# {{ .Title }} by Month
+{{ with .Classes }}
+Work: {{ range $i, $c := . }}{{ if $i }} · {{ end }}{{ if $c.Current }}**{{ $c.Class }}**{{ else }}[{{ md $c.Class }}]({{ link $c.Page }}){{ end }}{{ end }}
+{{ end }}{{ range .Months }}
+## {{ .Month }}
+{{ range .Sections }}{{ $h := "###" }}{{ if .Team }}{{ $h = "####" }}
+### {{ if .Page }}[{{ md .Team }}]({{ link .Page }}){{ else }}{{ md .Team }}{{ end }}
+{{ end }}
+| User | Open Issues | Closed Issues | Opened PRs | Closed PRs | Reviews | Comments | Commits |
+| --- | --- | --- | --- | --- | --- | --- | --- |
+{{ range .Users }}| {{ md .Name }} | {{ len (filterByAction .Activities "created_issue_open") }} | {{ len (filterByAction .Activities "created_issue_closed") }} | {{ len (filterByAction .Activities "opened_pr") }} | {{ len (filterByAction .Activities "closed_pr") }} | {{ len (filterByAction .Activities "reviewed_pr") }} | {{ len (filterByAction .Activities "commented") }} | {{ len (filterByAction .Activities "committed") }} |
+{{ end }}
+{{ range .Users }}
+{{ $h }} {{ md .Name }}{{ with .Classes }} — {{ range $i, $c := . }}{{ if $i }}, {{ end }}{{ $c.Class }} {{ $c.Count }}{{ end }}{{ end }}
+
+{{ range .Activities }}- **{{ actionLabel .Action }}** [{{ md .Title }}]({{ mdURL .URL }}){{ if .Detail }} ({{ .Detail }}){{ end }} in {{ .Repo }} on {{ formatDate .Timestamp }}{{ if .Class }} _({{ .Class }})_{{ end }}
+{{ end }}{{ end }}{{ end }}{{ end }}`,
+
+	PageCycleTime: `# PR Cycle Time
+
+Median / 90th percentile over the open PRs and the PRs closed in the past year. Times run from when the PR was opened; review rounds only count reviewed PRs.
+{{ template "groups" dict "Title" "By User" "Groups" .Users "Total" .All }}
+{{- template "groups" dict "Title" "By Repository" "Groups" .Repos }}
+{{- template "groups" dict "Title" "By Month Opened" "Groups" .Months }}
+## Pull Requests
+
+| Title | User | Repository | Opened | First Review | Merge | Review Rounds |
+| --- | --- | --- | --- | --- | --- | --- |
+{{ range .PRs }}| [{{ md .Title }}]({{ mdURL .URL }}) | {{ md .Name }} | {{ .Repo }} | {{ .CreatedAt }} | {{ if .Reviewed }}{{ formatHours .FirstReviewHours }}{{ else }}-{{ end }} | {{ if .Merged }}{{ formatHours .MergeHours }}{{ else }}-{{ end }} | {{ if .Reviewed }}{{ .Rounds }}{{ else }}-{{ end }} |
+{{ end }}
+{{- define "groups" }}
+## {{ .Title }}
+
+| | PRs | Time to First Review | Time to Merge | Review Rounds |
+| --- | --- | --- | --- | --- |
+{{ range .Groups }}| {{ md .Name }} | {{ .PRs }} | {{ template "hours" .FirstReview }} | {{ template "hours" .Merge }} | {{ template "rounds" .Rounds }} |
+{{ end }}{{ with .Total }}| **{{ .Name }}** | **{{ .PRs }}** | **{{ template "hours" .FirstReview }}** | **{{ template "hours" .Merge }}** | **{{ template "rounds" .Rounds }}** |
+{{ end }}{{ end }}
+{{- define "hours" }}{{ if .Count }}{{ formatHours .Median }} / {{ formatHours .P90 }}{{ else }}-{{ end }}{{ end }}
+{{- define "rounds" }}{{ if .Count }}{{ printf "%.0f" .Median }} / {{ printf "%.0f" .P90 }}{{ else }}-{{ end }}{{ end }}`,
+
+	PageStale: `# Needs Attention
+
+Open PRs and assigned issues{{ with .Thresholds.DaysSinceUpdate }} not updated for {{ . }} days{{ end }}{{ with .Thresholds.DaysSinceComment }}{{ if $.Thresholds.DaysSinceUpdate }}, or{{ end }} without a reply from anyone but their author for {{ . }} days{{ end }}, longest untouched first.
+{{ if .Items }}
+| Title | Kind | User | Repository | Days Since Update | Why |
+| --- | --- | --- | --- | --- | --- |
+{{ range .Items }}| [{{ md .Issue.Title }}]({{ mdURL .Issue.URL }}) | {{ .Kind }} | {{ md .Name }} | {{ .Issue.Repo }} | {{ .AgeDays }} | {{ .Reason }} |
+{{ end }}{{ else }}
+Nothing needs attention.
+{{ end }}`,
+
+	PageLeaderboard: `# Leaderboard
+
+Each activity scores the weight of its kind, multiplied for its repo tier, labels and PR size.
+
+## Last {{ .RollingDays }} Days
+{{ if .Rolling }}
+{{ template "entries" .Rolling }}
+### How the Scores Add Up
+{{ range .Rolling }}
+#### {{ md .Name }}: {{ formatPoints .Points }} points
+
+| Title | Kind | Repository | Date | Points | Score |
+| --- | --- | --- | --- | --- | --- |
+{{ range .Breakdown }}| [{{ md .Title }}]({{ mdURL .URL }}) | {{ actionLabel .Kind }} | {{ .Repo }} | {{ .Timestamp.Format "2006-01-02" }} | {{ formatPoints .Points }} | {{ .Explain }} |
+{{ end }}{{ end }}{{ else }}
+No activity in the last {{ .RollingDays }} days.
+{{ end }}
+## By Month
+{{ range .Months }}
+### {{ .Month }}
+
+{{ template "entries" .Entries }}{{ else }}
+No activity.
+{{ end }}
+## Weights
+
+| Kind | Points |
+| --- | --- |
+{{ range $kind, $weight := .Weights }}| {{ actionLabel $kind }} | {{ formatPoints $weight }} |
+{{ end }}
+{{- define "entries" -}}
+| Rank | User | Points | Made Of |
+| --- | --- | --- | --- |
+{{ range . }}| {{ .Rank }} | {{ md .Name }} | {{ formatPoints .Points }} | {{ range $i, $k := .Kinds }}{{ if $i }}, {{ end }}{{ $k.Count }} × {{ actionLabel $k.Kind }} ({{ formatPoints $k.Points }}){{ end }} |
+{{ end }}{{ end }}`,
+
+	PageTeams: `# Teams
+
+| Team | Leads | Members | Assigned Issues | Created Issues | Open PRs | Closed PRs (past 1 year) | Merged PRs | Activity in {{ .Month }} |
+| --- | --- | --- | --- | --- | --- | --- | --- | --- |
+{{ range .Teams }}| [{{ md .Team }}]({{ link .Page }}) | {{ join .Leads ", " }} | {{ .Members }} | {{ .AssignedIssues }} | {{ .CreatedIssues }} | {{ .OpenPRs }} | {{ .ClosedPRs }} | {{ .MergedPRs }} | {{ .Activity }} |
+{{ end }}{{ with .Org }}| **{{ .Team }}** | | **{{ .Members }}** | **{{ .AssignedIssues }}** | **{{ .CreatedIssues }}** | **{{ .OpenPRs }}** | **{{ .ClosedPRs }}** | **{{ .MergedPRs }}** | **{{ .Activity }}** |
+{{ end }}
+Issues and PRs shared by several members are counted once per team and once overall.
+`,
+
+	PageUserChanges: `# Tracked User Changes
+{{ if .Since.IsZero }}
+This is the first run; {{ .Users }} users are tracked.
+{{ else }}
+{{ .Users }} users are tracked. Compared with the previous run on {{ .Since.Format "2006-01-02 15:04 MST" }}:
+{{ if or .Added .Removed }}
+{{ range .Added }}- **Added** [{{ . }}](https://github.com/{{ . }})
+{{ end }}{{ range .Removed }}- **Removed** [{{ . }}](https://github.com/{{ . }})
+{{ end }}{{ else }}
+No users were added or removed.
+{{ end }}{{ end }}{{ if .History }}
+## History
+
+| Date | Added | Removed |
+| --- | --- | --- |
+{{ range .History }}| {{ .At.Format "2006-01-02" }} | {{ join .Added ", " }} | {{ join .Removed ", " }} |
+{{ end }}{{ end }}`,
+
+	PageKubernetes: `# Kubernetes PR Contributions
+
+| User | Pull Requests |
+| --- | --- |
+{{ range .Users }}| {{ md .Name }} | {{ if .PRs }}{{ range $i, $pr := .PRs }}{{ if $i }}, {{ end }}[{{ $pr.Repo }}]({{ mdURL $pr.URL }} "{{ md $pr.Title }}"){{ end }}{{ else }}_No PRs_{{ end }} |
+{{ end }}`,
+}
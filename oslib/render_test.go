@@ -0,0 +1,48 @@
+package oslib
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHTMLRendererEscapesGitHubText(t *testing.T) {
+	evil := `<script>alert(1)</script>`
+	report := IssuesReport{
+		Label:  "good first issue",
+		Title:  evil,
+		Issues: []Issue{{Title: evil, URL: "javascript:alert(1)", Repo: "r"}},
+		LikelyTaken: []TakenIssue{{
+			Issue: Issue{Title: "taken", URL: "https://github.com/o/r/issues/1", Repo: "r"},
+			Claim: Claim{By: `<img src=x onerror=alert(1)>`, URL: "https://github.com/o/r/issues/1#c"},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := (htmlRenderer{}).Render(&buf, Page{Kind: PageIssues, Name: "gfi", Data: report}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, raw := range []string{evil, `<img src=x`, `href="javascript:`} {
+		if strings.Contains(out, raw) {
+			t.Errorf("rendered page contains %q unescaped", raw)
+		}
+	}
+	if !strings.Contains(out, "&lt;script&gt;") {
+		t.Errorf("rendered page lost the escaped title")
+	}
+}
+
+func TestMarkdownRendererKeepsLinksIntact(t *testing.T) {
+	report := IssuesReport{
+		Title:  "Good first issues",
+		Issues: []Issue{{Title: `Fix \ it](https://evil.example) [x|y] (see #1)`, URL: "https://github.com/o/r/issues/1?q=(a b)|c", Repo: "r"}},
+	}
+	var buf bytes.Buffer
+	if err := (markdownRenderer{}).Render(&buf, Page{Kind: PageIssues, Name: "gfi", Data: report}); err != nil {
+		t.Fatal(err)
+	}
+	want := `| [Fix \\ it\]\(https://evil.example\) \[x\|y\] \(see #1\)](https://github.com/o/r/issues/1?q=%28a%20b%29%7Cc) | r |`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("rendered markdown:\n%s\nwant a row starting %s", buf.String(), want)
+	}
+}
@@ -0,0 +1,153 @@
+package oslib
+
+import (
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// textRenderer writes aligned plain-text tables for reading in a terminal.
+type textRenderer struct{}
+
+func (textRenderer) Ext() string { return ".txt" }
+
+func (textRenderer) Render(w io.Writer, p Page) error {
+	funcs := baseFuncs(".txt")
+	funcs["cell"] = func(s string) string {
+		s = strings.ReplaceAll(s, "\t", " ")
+		return strings.ReplaceAll(s, "\n", " ")
+	}
+	funcs["upper"] = strings.ToUpper
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if err := renderTemplate(tw, "text", textTemplates, funcs, p); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+var textTemplates = map[string]string{
+	PageIssues: `{{ .Title }}
+{{ if .Issues }}TITLE	REPOSITORY	CREATED AT	URL
+{{ range .Issues }}{{ cell .Title }}	{{ .Repo }}	{{ .CreatedAt }}	{{ .URL }}
+{{ end }}{{ else }}No issues found
+{{ end }}{{ if .LikelyTaken }}
+LIKELY TAKEN
+TITLE	REPOSITORY	CLAIMED BY	CLAIMED AT	URL
+{{ range .LikelyTaken }}{{ cell .Title }}	{{ .Repo }}	{{ .Claim.By }}	{{ .Claim.At }}	{{ .URL }}
+{{ end }}{{ end }}`,
+
+	PageLabelIndex: `ISSUE LABELS
+LABEL	OPEN ISSUES	LIKELY TAKEN	PAGE
+{{ range .Labels }}{{ .Title }}	{{ .Count }}	{{ .Taken }}	{{ link .Page }}
+{{ end }}`,
+
+	PageDashboard: `{{ upper .Title }}
+{{ with .Classes }}WORK:{{ range . }} {{ if .Current }}[{{ .Class }}]{{ else }}{{ .Class }} ({{ link .Page }}){{ end }}{{ end }}
+{{ end }}{{ range .Sections }}{{ if .Team }}
+### {{ upper .Team }}{{ if .Leads }} (led by {{ join .Leads ", " }}){{ end }} ###
+{{ end }}{{ range .Users }}
+== {{ .Name }}{{ if .Lead }} (lead){{ end }}{{ if .StaleCount }} ({{ .StaleCount }} stale){{ end }}{{ range .Classes }} ({{ .Class }} {{ .Count }}){{ end }} ==
+{{ if $.ShowWaiting }}Waiting on Me:
+{{ if .WaitingOnMe }}  TITLE	REPOSITORY	WHY	LAST ACTION	URL
+{{ range .WaitingOnMe }}  {{ cell .Issue.Title }}	{{ .Issue.Repo }}	{{ join .Reasons ", " }}	{{ if .LastActor }}{{ .LastActor }} at {{ .LastActionAt }}{{ else }}opened {{ .Issue.CreatedAt }}{{ end }}	{{ .Issue.URL }}
+{{ end }}{{ else }}  none
+{{ end }}{{ end }}{{ template "bucket" dict "Title" "Assigned Issues" "Items" .AssignedIssues "Stale" .Stale "Status" nil }}
+{{- template "bucket" dict "Title" "Created Issues" "Items" .CreatedIssues "Stale" nil "Status" nil }}
+{{- template "bucket" dict "Title" "Open PRs" "Items" .OpenPRs "Stale" .Stale "Status" .PRStatus }}
+{{- template "bucket" dict "Title" "Closed PRs (past 1 year)" "Items" .ClosedPRs "Stale" nil "Status" nil }}
+{{ if $.ShowReviews }}Reviews (past 1 year):
+{{ if .Reviews }}  TITLE	REPOSITORY	REVIEW	SUBMITTED AT	URL
+{{ range .Reviews }}  {{ cell .PR.Title }}	{{ .PR.Repo }}	{{ .StateLabel }}	{{ .SubmittedAt }}	{{ .PR.URL }}
+{{ end }}{{ else }}  none
+{{ end }}{{ end }}{{ if $.ShowCommits }}
+Commits (past 1 year):
+{{ if .Commits }}  SHA	MESSAGE	REPOSITORY	COMMITTED AT	URL
+{{ range .Commits }}  {{ .ShortSHA }}{{ if .CoAuthored }} (co-author){{ end }}	{{ cell .Message }}	{{ .Repo }}	{{ .Date }}	{{ .URL }}
+{{ end }}{{ else }}  none
+{{ end }}{{ end }}
+{{- end }}{{ end }}
+{{- define "bucket" }}
+{{ .Title }}:
+{{ if .Items }}  TITLE	REPOSITORY	UPDATED AT	URL
+{{ range $item := .Items }}  {{ if $.Stale }}{{ if index $.Stale $item.URL }}[stale] {{ end }}{{ end }}{{ cell .Title }}{{ if $.Status }}{{ range (index $.Status $item.URL).Badges }} [{{ .Label }}]{{ end }}{{ end }}	{{ .Repo }}	{{ .UpdatedAt }}	{{ .URL }}
+{{ end }}{{ else }}  none
+{{ end }}{{ end }}`,
+
+	PageAchievements: `{{ upper .Title }} BY MONTH
+{{ with .Classes }}WORK:{{ range . }} {{ if .Current }}[{{ .Class }}]{{ else }}{{ .Class }} ({{ link .Page }}){{ end }}{{ end }}
+{{ end }}{{ range .Months }}
+== {{ .Month }} ==
+{{ range .Sections }}{{ if .Team }}{{ .Team }}:
+{{ end }}USER	OPEN ISSUES	CLOSED ISSUES	OPENED PRS	CLOSED PRS	REVIEWS	COMMENTS	COMMITS{{ if $.Classes }}	CLASSES{{ end }}
+{{ range .Users }}{{ cell .Name }}	{{ len (filterByAction .Activities "created_issue_open") }}	{{ len (filterByAction .Activities "created_issue_closed") }}	{{ len (filterByAction .Activities "opened_pr") }}	{{ len (filterByAction .Activities "closed_pr") }}	{{ len (filterByAction .Activities "reviewed_pr") }}	{{ len (filterByAction .Activities "commented") }}	{{ len (filterByAction .Activities "committed") }}{{ if $.Classes }}	{{ range $i, $c := .Classes }}{{ if $i }}, {{ end }}{{ $c.Class }} {{ $c.Count }}{{ end }}{{ end }}
+{{ end }}{{ end }}{{ end }}`,
+
+	PageCycleTime: `PR CYCLE TIME (MEDIAN / P90)
+{{ template "groups" dict "Title" "BY USER" "Groups" .Users "Total" .All }}
+{{- template "groups" dict "Title" "BY REPOSITORY" "Groups" .Repos }}
+{{- template "groups" dict "Title" "BY MONTH OPENED" "Groups" .Months }}
+PULL REQUESTS
+TITLE	USER	REPOSITORY	OPENED	FIRST REVIEW	MERGE	ROUNDS	URL
+{{ range .PRs }}{{ cell .Title }}	{{ cell .Name }}	{{ .Repo }}	{{ .CreatedAt }}	{{ if .Reviewed }}{{ formatHours .FirstReviewHours }}{{ else }}-{{ end }}	{{ if .Merged }}{{ formatHours .MergeHours }}{{ else }}-{{ end }}	{{ if .Reviewed }}{{ .Rounds }}{{ else }}-{{ end }}	{{ .URL }}
+{{ end }}
+{{- define "groups" }}
+{{ .Title }}
+NAME	PRS	FIRST REVIEW	MERGE	ROUNDS
+{{ range .Groups }}{{ cell .Name }}	{{ .PRs }}	{{ template "hours" .FirstReview }}	{{ template "hours" .Merge }}	{{ template "rounds" .Rounds }}
+{{ end }}{{ with .Total }}{{ cell .Name }}	{{ .PRs }}	{{ template "hours" .FirstReview }}	{{ template "hours" .Merge }}	{{ template "rounds" .Rounds }}
+{{ end }}{{ end }}
+{{- define "hours" }}{{ if .Count }}{{ formatHours .Median }} / {{ formatHours .P90 }}{{ else }}-{{ end }}{{ end }}
+{{- define "rounds" }}{{ if .Count }}{{ printf "%.0f" .Median }} / {{ printf "%.0f" .P90 }}{{ else }}-{{ end }}{{ end }}`,
+
+	PageStale: `NEEDS ATTENTION
+{{ if .Items }}TITLE	KIND	USER	REPOSITORY	DAYS SINCE UPDATE	WHY	URL
+{{ range .Items }}{{ cell .Issue.Title }}	{{ .Kind }}	{{ cell .Name }}	{{ .Issue.Repo }}	{{ .AgeDays }}	{{ .Reason }}	{{ .Issue.URL }}
+{{ end }}{{ else }}Nothing needs attention.
+{{ end }}`,
+
+	PageLeaderboard: `LEADERBOARD
+
+LAST {{ .RollingDays }} DAYS
+{{ if .Rolling }}{{ template "entries" .Rolling }}
+{{ range .Rolling }}
+== {{ .Name }}: {{ formatPoints .Points }} points ==
+  TITLE	KIND	REPOSITORY	DATE	POINTS	SCORE
+{{ range .Breakdown }}  {{ cell .Title }}	{{ actionLabel .Kind }}	{{ .Repo }}	{{ .Timestamp.Format "2006-01-02" }}	{{ formatPoints .Points }}	{{ .Explain }}
+{{ end }}{{ end }}{{ else }}No activity in the last {{ .RollingDays }} days.
+{{ end }}
+BY MONTH
+{{ range .Months }}
+== {{ .Month }} ==
+{{ template "entries" .Entries }}{{ else }}No activity.
+{{ end }}
+WEIGHTS
+{{ range $kind, $weight := .Weights }}  {{ actionLabel $kind }}	{{ formatPoints $weight }}
+{{ end }}
+{{- define "entries" }}RANK	USER	POINTS	MADE OF
+{{ range . }}{{ .Rank }}	{{ cell .Name }}	{{ formatPoints .Points }}	{{ range $i, $k := .Kinds }}{{ if $i }}, {{ end }}{{ $k.Count }} x {{ actionLabel $k.Kind }} ({{ formatPoints $k.Points }}){{ end }}
+{{ end }}{{ end }}`,
+
+	PageTeams: `TEAMS
+TEAM	LEADS	MEMBERS	ASSIGNED	CREATED	OPEN PRS	CLOSED PRS	MERGED PRS	ACTIVITY IN {{ .Month }}
+{{ range .Teams }}{{ .Team }}	{{ join .Leads "," }}	{{ .Members }}	{{ .AssignedIssues }}	{{ .CreatedIssues }}	{{ .OpenPRs }}	{{ .ClosedPRs }}	{{ .MergedPRs }}	{{ .Activity }}
+{{ end }}{{ with .Org }}{{ .Team }}	-	{{ .Members }}	{{ .AssignedIssues }}	{{ .CreatedIssues }}	{{ .OpenPRs }}	{{ .ClosedPRs }}	{{ .MergedPRs }}	{{ .Activity }}
+{{ end }}`,
+
+	PageUserChanges: `TRACKED USER CHANGES
+{{ if .Since.IsZero }}First run: {{ .Users }} users tracked.
+{{ else }}{{ .Users }} users tracked. Since {{ .Since.Format "2006-01-02 15:04 MST" }}:
+{{ range .Added }}  + {{ . }}
+{{ end }}{{ range .Removed }}  - {{ . }}
+{{ else }}{{ if not .Added }}  no changes
+{{ end }}{{ end }}{{ end }}{{ if .History }}
+HISTORY
+DATE	ADDED	REMOVED
+{{ range .History }}{{ .At.Format "2006-01-02" }}	{{ join .Added "," }}	{{ join .Removed "," }}
+{{ end }}{{ end }}`,
+
+	PageKubernetes: `KUBERNETES PR CONTRIBUTIONS
+USER	REPOSITORY	TITLE	URL
+{{ range .Users }}{{ $user := cell .Name }}{{ range .PRs }}{{ $user }}	{{ .Repo }}	{{ cell .Title }}	{{ .URL }}
+{{ else }}{{ $user }}	-	No PRs	
+{{ end }}{{ end }}`,
+}
@@ -0,0 +1,298 @@
+package oslib
+
+import (
+	"sort"
+	"time"
+)
+
+// DashboardReport is the model behind user_dashboard and the per-team dashboards:
+// the issue and PR buckets of every user, in one section per team.
+type DashboardReport struct {
+	Title    string
+	Sections []DashboardSection
+	// Classes links the dashboard of every work class, when classes are configured.
+	Classes []ClassLink `json:",omitempty"`
+	// ShowCommits, ShowReviews and ShowWaiting add those sections per user.
+	ShowCommits bool `json:",omitempty"`
+	ShowReviews bool `json:",omitempty"`
+	ShowWaiting bool `json:",omitempty"`
+	Stale       Staleness
+}
+
+// DashboardSection is one team's users. Without teams there is a single section with no Team.
+type DashboardSection struct {
+	Team string `json:",omitempty"`
+	// Page is the base file name of the team's own dashboard, when linked.
+	Page  string   `json:",omitempty"`
+	Leads []string `json:",omitempty"`
+	Users []UserBuckets
+}
+
+type UserBuckets struct {
+	User   string
+	Name   string
+	Avatar string `json:",omitempty"`
+	Lead   bool   `json:",omitempty"`
+	// Classes counts the user's distinct issues and PRs per work class, when classes are configured.
+	Classes        []ClassCount `json:",omitempty"`
+	AssignedIssues []Issue
+	CreatedIssues  []Issue
+	OpenPRs        []Issue
+	ClosedPRs      []Issue
+	Reviews        []Review
+	Commits        []Commit `json:",omitempty"`
+	// PRStatus maps the URLs of OpenPRs to their review, CI and merge state.
+	PRStatus map[string]PRStatus `json:",omitempty"`
+	// WaitingOnMe is the user's to-do list, longest waiting first.
+	WaitingOnMe []WaitingItem
+	// Stale maps the URLs of stale open PRs and assigned issues to why they are stale.
+	Stale      map[string]string `json:",omitempty"`
+	StaleCount int
+}
+
+// IssuesReport is the model behind a label page such as good_first_issues.
+type IssuesReport struct {
+	Label  string
+	Title  string
+	Issues []Issue
+	// LikelyTaken are the issues someone asked to work on in a comment, kept out of Issues.
+	LikelyTaken []TakenIssue `json:",omitempty"`
+	// Feed is the base name of the Atom and JSON feeds for this page, if any.
+	Feed string `json:",omitempty"`
+}
+
+// LabelIndexReport is the model behind the labels page that links every label page.
+type LabelIndexReport struct {
+	Labels []LabelSummary
+	// Feeds is set when Atom and JSON feeds were written next to the label pages.
+	Feeds bool `json:",omitempty"`
+}
+
+type LabelSummary struct {
+	Label string
+	Title string
+	// Page is the base file name of the label page.
+	Page  string
+	Count int
+	// Taken counts the issues claimed in comments, which Count leaves out.
+	Taken int `json:",omitempty"`
+}
+
+// AchievementsReport is the model behind team_achievements and the per-team
+// achievements pages, newest month first.
+type AchievementsReport struct {
+	Title  string
+	Months []MonthlyActivity
+	// Classes links the achievements of every work class, when classes are configured.
+	Classes []ClassLink `json:",omitempty"`
+}
+
+// ClassLink is one page of a report split by work class. Class "all" is the unsplit page.
+type ClassLink struct {
+	Class   string
+	Page    string
+	Current bool
+}
+
+type ClassCount struct {
+	Class string
+	Count int
+}
+
+type MonthlyActivity struct {
+	Month    string
+	Sections []AchievementsSection
+}
+
+// AchievementsSection is one team's activity in a month. Without teams there is a single section with no Team.
+type AchievementsSection struct {
+	Team  string `json:",omitempty"`
+	Page  string `json:",omitempty"`
+	Users []MonthlyUserActivity
+}
+
+// TeamsReport is the model behind teams: each team's totals, rolled up into
+// an org-wide total that counts every user and item once.
+type TeamsReport struct {
+	// Month is the month the Activity counts cover.
+	Month string
+	Teams []TeamSummary
+	Org   TeamSummary
+}
+
+type TeamSummary struct {
+	Team           string
+	Page           string   `json:",omitempty"`
+	Leads          []string `json:",omitempty"`
+	Members        int
+	AssignedIssues int
+	CreatedIssues  int
+	OpenPRs        int
+	ClosedPRs      int
+	MergedPRs      int
+	Activity       int
+}
+
+// CycleTimeReport is the model behind pr_cycle_time: how long the tracked users'
+// PRs waited for a first review and for merging, and how many review rounds they took.
+// Times are in hours.
+type CycleTimeReport struct {
+	All    CycleGroup
+	Users  []CycleGroup
+	Repos  []CycleGroup
+	Months []CycleGroup
+	PRs    []PRCycleRow
+}
+
+// CycleGroup summarises the PRs of one user, repo or month.
+type CycleGroup struct {
+	Name        string
+	PRs         int
+	FirstReview CycleStat
+	Merge       CycleStat
+	Rounds      CycleStat
+}
+
+// CycleStat is the median and 90th percentile of Count values.
+type CycleStat struct {
+	Count  int
+	Median float64
+	P90    float64
+}
+
+type PRCycleRow struct {
+	User             string
+	Name             string
+	Title            string
+	URL              string
+	Repo             string
+	CreatedAt        string
+	Reviewed         bool
+	FirstReviewHours float64 `json:",omitempty"`
+	Merged           bool
+	MergeHours       float64 `json:",omitempty"`
+	Rounds           int
+}
+
+// StaleReport is the model behind needs_attention: every stale open PR and
+// assigned issue, longest untouched first.
+type StaleReport struct {
+	Thresholds Staleness
+	Items      []StaleItem
+}
+
+type StaleItem struct {
+	User    string
+	Name    string
+	Kind    string
+	Issue   Issue
+	AgeDays int
+	Reason  string
+}
+
+// LeaderboardReport is the model behind the leaderboard page.
+type LeaderboardReport struct {
+	RollingDays int
+	Rolling     []LeaderboardEntry
+	Months      []LeaderboardMonth
+	// Weights are the points per activity kind the scores used.
+	Weights map[string]float64
+}
+
+type LeaderboardMonth struct {
+	Month   string // "2025-07"
+	Entries []LeaderboardEntry
+}
+
+type LeaderboardEntry struct {
+	Rank   int
+	User   string
+	Name   string
+	Avatar string `json:",omitempty"`
+	Points float64
+	// Kinds sums the points per activity kind, highest first.
+	Kinds []KindScore
+	// Breakdown is every scored activity, highest first. Only the rolling leaderboard has it.
+	Breakdown []ScoredActivity `json:",omitempty"`
+}
+
+type KindScore struct {
+	Kind   string
+	Count  int
+	Points float64
+}
+
+// UserChangesReport is the model behind user_changes: who started or stopped
+// being tracked since the previous run, and the changes recorded before it.
+type UserChangesReport struct {
+	// Since is when the previous run happened; it is zero on the first run.
+	Since   time.Time
+	Users   int
+	Added   []string
+	Removed []string
+	History []UserChange
+}
+
+type UserChange struct {
+	At      time.Time `json:"at"`
+	Added   []string  `json:"added,omitempty"`
+	Removed []string  `json:"removed,omitempty"`
+}
+
+// KubernetesReport is the model behind kubernetes_contributions.
+type KubernetesReport struct {
+	Users []UserPRs
+}
+
+type UserPRs struct {
+	User string
+	Name string
+	PRs  []Issue
+}
+
+// newAchievementsReport splits each month into one section per group, leaving
+// out groups and months without activity. person supplies the name shown for each login.
+func newAchievementsReport(title string, grouped map[string]map[string][]Activity, months []string, groups []userGroup, linkTeams bool, person func(string) User) AchievementsReport {
+	report := AchievementsReport{Title: title}
+	for _, month := range months {
+		entry := MonthlyActivity{Month: month}
+		for _, g := range groups {
+			section := AchievementsSection{Team: g.Team.Name}
+			if linkTeams {
+				section.Page = g.page(teamAchievementsPage)
+			}
+			for _, user := range sortedKeys(grouped[month]) {
+				if g.Users != nil && !containsFold(g.Users, user) {
+					continue
+				}
+				section.Users = append(section.Users, MonthlyUserActivity{
+					User:       user,
+					Name:       person(user).DisplayName(),
+					Avatar:     person(user).Avatar,
+					Month:      month,
+					Activities: grouped[month][user],
+					Classes:    countClasses(activityClasses(grouped[month][user])),
+				})
+			}
+			sort.SliceStable(section.Users, func(i, j int) bool {
+				return section.Users[i].Name < section.Users[j].Name
+			})
+			if section.Users != nil {
+				entry.Sections = append(entry.Sections, section)
+			}
+		}
+		if entry.Sections != nil {
+			report.Months = append(report.Months, entry)
+		}
+	}
+	return report
+}
+
+// NewKubernetesReport turns the output of FetchKubernetesPRs into a report model.
+func NewKubernetesReport(prsByUser map[string][]Issue) KubernetesReport {
+	var report KubernetesReport
+	for _, user := range sortedKeys(prsByUser) {
+		report.Users = append(report.Users, UserPRs{User: user, Name: user, PRs: prsByUser[user]})
+	}
+	return report
+}
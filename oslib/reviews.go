@@ -0,0 +1,100 @@
+package oslib
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Review is one review a user submitted on someone else's pull request.
+type Review struct {
+	PR Issue `json:"pr"`
+	// State is APPROVED, CHANGES_REQUESTED or COMMENTED.
+	State       string `json:"state"`
+	SubmittedAt string `json:"submitted_at"`
+}
+
+// StateLabel is the review state as reports show it, e.g. "changes requested".
+func (r Review) StateLabel() string {
+	return strings.ReplaceAll(strings.ToLower(r.State), "_", " ")
+}
+
+// reviewStates are the review states that count; pending and dismissed reviews do not.
+var reviewStates = []string{"APPROVED", "CHANGES_REQUESTED", "COMMENTED"}
+
+func reviewedPRsQuery(username string) SearchQuery {
+	oneYearAgo := time.Now().AddDate(-1, 0, 0)
+	return NewSearchQuery().With("reviewed-by", username).Without("author", username).Is("pr").Updated(Since(oneYearAgo))
+}
+
+func fetchReviews(ctx context.Context, q SearchQuery, username, token string) ([]Review, error) {
+	prs, err := searchIssues(ctx, q, token)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(-1, 0, 0).UTC().Format(time.RFC3339)
+	var reviews []Review
+	for _, pr := range prs {
+		prReviews, err := fetchPRReviews(ctx, pr, token)
+		if err != nil {
+			return nil, fmt.Errorf("fetching reviews of %s: %w", pr.URL, err)
+		}
+		// Replying to review threads files a COMMENTED review each time; count one per state and day.
+		seen := make(map[string]bool)
+		for _, r := range prReviews {
+			if !strings.EqualFold(r.User.Login, username) || !containsFold(reviewStates, r.State) || r.SubmittedAt < since {
+				continue
+			}
+			key := r.State + r.SubmittedAt[:min(len(r.SubmittedAt), len("2006-01-02"))]
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			reviews = append(reviews, Review{PR: pr, State: r.State, SubmittedAt: r.SubmittedAt})
+		}
+	}
+	sortReviews(reviews)
+	return reviews, nil
+}
+
+type prReview struct {
+	User        webhookLogin `json:"user"`
+	State       string       `json:"state"`
+	SubmittedAt string       `json:"submitted_at"`
+}
+
+func fetchPRReviews(ctx context.Context, pr Issue, token string) ([]prReview, error) {
+	var all []prReview
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/reviews?per_page=100&page=%d", url.PathEscape(pr.Owner), url.PathEscape(pr.Repo), pr.Number, page)
+		var reviews []prReview
+		if err := getGitHubJSON(ctx, apiURL, token, &reviews); err != nil {
+			return nil, err
+		}
+		all = append(all, reviews...)
+		if len(reviews) < 100 {
+			return all, nil
+		}
+	}
+}
+
+func sortReviews(reviews []Review) {
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].SubmittedAt > reviews[j].SubmittedAt
+	})
+}
+
+// upsertReview adds r unless the same review is already listed, keeping the list newest first.
+func upsertReview(list *[]Review, r Review) {
+	for _, existing := range *list {
+		if existing.PR.URL == r.PR.URL && existing.SubmittedAt == r.SubmittedAt {
+			return
+		}
+	}
+	*list = append(*list, r)
+	sortReviews(*list)
+}
@@ -0,0 +1,317 @@
+package oslib
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Scoring weighs activity into points for the leaderboard. An activity scores
+// its kind's weight times the multipliers of its tier, labels and PR size.
+type Scoring struct {
+	// Disabled turns the leaderboard off while keeping its settings.
+	Disabled bool `json:"disabled" yaml:"disabled"`
+	// Weights are the points per activity kind; kinds left out keep their default weight.
+	Weights map[string]float64 `json:"weights" yaml:"weights"`
+	// Tiers multiply activity in some orgs or repos. The first matching tier applies.
+	Tiers []ScoreTier `json:"tiers" yaml:"tiers"`
+	// Labels multiply issues and PRs carrying them, keyed by label name.
+	Labels map[string]float64 `json:"labels" yaml:"labels"`
+	// Sizes multiply the user's own PRs by lines changed. The first size a PR
+	// fits applies. They cost one API call per PR, so none are set by default.
+	Sizes []ScoreSize `json:"sizes" yaml:"sizes"`
+	// RollingDays is the window of the rolling leaderboard.
+	RollingDays int `json:"rolling_days" yaml:"rolling_days"`
+}
+
+// ScoreTier is a group of orgs and repos (owner/name) whose activity counts Multiplier times.
+type ScoreTier struct {
+	Name       string   `json:"name" yaml:"name"`
+	Orgs       []string `json:"orgs" yaml:"orgs"`
+	Repos      []string `json:"repos" yaml:"repos"`
+	Multiplier float64  `json:"multiplier" yaml:"multiplier"`
+}
+
+// ScoreSize applies to PRs changing at most MaxLines lines; a zero MaxLines fits any PR.
+type ScoreSize struct {
+	MaxLines   int     `json:"max_lines" yaml:"max_lines"`
+	Multiplier float64 `json:"multiplier" yaml:"multiplier"`
+}
+
+// defaultWeights score a merged PR above opening one, and a comment below either.
+// merged_pr replaces closed_pr for PRs that were merged.
+var defaultWeights = map[string]float64{
+	"opened_pr":            2,
+	"merged_pr":            5,
+	"closed_pr":            1,
+	"created_issue_open":   1,
+	"created_issue_closed": 1,
+	"reviewed_pr":          2,
+	"commented":            0.5,
+	"committed":            1,
+}
+
+const defaultRollingDays = 90
+
+// scoring is the config's scoring with defaults filled in, or nil when it is
+// disabled. Ranking people is opt-in: without a scoring section there is no leaderboard.
+func (c *Config) scoring() *Scoring {
+	if c == nil || c.Scoring == nil || c.Scoring.Disabled {
+		return nil
+	}
+	s := *c.Scoring
+	weights := make(map[string]float64, len(defaultWeights))
+	for kind, w := range defaultWeights {
+		weights[kind] = w
+	}
+	for kind, w := range s.Weights {
+		weights[kind] = w
+	}
+	s.Weights = weights
+	if s.RollingDays == 0 {
+		s.RollingDays = defaultRollingDays
+	}
+	return &s
+}
+
+func (s *Scoring) validate(l configLocator, errs *ConfigErrors) {
+	for _, kind := range sortedKeys(s.Weights) {
+		if _, ok := defaultWeights[kind]; !ok {
+			errs.add(l, []interface{}{"scoring", "weights", kind}, "%q is not an activity kind; use one of %s", kind, strings.Join(sortedKeys(defaultWeights), ", "))
+		} else if s.Weights[kind] < 0 {
+			errs.add(l, []interface{}{"scoring", "weights", kind}, "must not be negative")
+		}
+	}
+	for i, t := range s.Tiers {
+		if strings.TrimSpace(t.Name) == "" {
+			errs.add(l, []interface{}{"scoring", "tiers", i}, "tier name is required")
+		}
+		if len(t.Orgs) == 0 && len(t.Repos) == 0 {
+			errs.add(l, []interface{}{"scoring", "tiers", i}, "a tier needs orgs or repos")
+		}
+		if t.Multiplier <= 0 {
+			errs.add(l, []interface{}{"scoring", "tiers", i, "multiplier"}, "must be positive")
+		}
+		checkLogins(l, errs, []interface{}{"scoring", "tiers", i, "orgs"}, t.Orgs)
+		checkRepos(l, errs, []interface{}{"scoring", "tiers", i, "repos"}, t.Repos)
+	}
+	for _, name := range sortedKeys(s.Labels) {
+		if s.Labels[name] < 0 {
+			errs.add(l, []interface{}{"scoring", "labels", name}, "must not be negative")
+		}
+	}
+	for i, size := range s.Sizes {
+		switch {
+		case size.Multiplier <= 0:
+			errs.add(l, []interface{}{"scoring", "sizes", i, "multiplier"}, "must be positive")
+		case size.MaxLines < 0:
+			errs.add(l, []interface{}{"scoring", "sizes", i, "max_lines"}, "must not be negative")
+		case i > 0 && (s.Sizes[i-1].MaxLines == 0 || size.MaxLines != 0 && size.MaxLines <= s.Sizes[i-1].MaxLines):
+			errs.add(l, []interface{}{"scoring", "sizes", i}, "sizes must go from smallest to largest, with only the last one open-ended")
+		}
+	}
+	if s.RollingDays < 0 {
+		errs.add(l, []interface{}{"scoring", "rolling_days"}, "must not be negative")
+	}
+}
+
+// ScoringEnabled reports whether the leaderboard is generated.
+func (c *Config) ScoringEnabled() bool {
+	return c.scoring() != nil
+}
+
+// ScoreFactor is one part of a score: the kind's weight, or a multiplier applied to it.
+type ScoreFactor struct {
+	Name  string
+	Value float64
+}
+
+// ScoredActivity is an activity with its points and the factors they came from.
+type ScoredActivity struct {
+	Activity
+	Kind    string
+	Points  float64
+	Factors []ScoreFactor
+}
+
+// Explain spells the score out, e.g. "merged_pr 5 × tier core 2 = 10".
+func (s ScoredActivity) Explain() string {
+	parts := make([]string, len(s.Factors))
+	for i, f := range s.Factors {
+		parts[i] = f.Name + " " + formatPoints(f.Value)
+	}
+	return strings.Join(parts, " × ") + " = " + formatPoints(s.Points)
+}
+
+// score weighs a, of the given kind. lines is the PR's size when known, or -1.
+func (s *Scoring) score(a Activity, kind string, lines int) ScoredActivity {
+	scored := ScoredActivity{Activity: a, Kind: kind}
+	points := s.Weights[kind]
+	scored.Factors = append(scored.Factors, ScoreFactor{Name: kind, Value: points})
+	if tier, ok := s.tier(a.Owner, a.Repo); ok {
+		scored.Factors = append(scored.Factors, ScoreFactor{Name: "tier " + tier.Name, Value: tier.Multiplier})
+		points *= tier.Multiplier
+	}
+	for _, name := range sortedKeys(s.Labels) {
+		if containsFold(a.Labels, name) {
+			scored.Factors = append(scored.Factors, ScoreFactor{Name: "label " + name, Value: s.Labels[name]})
+			points *= s.Labels[name]
+		}
+	}
+	if lines >= 0 {
+		for _, size := range s.Sizes {
+			if size.MaxLines == 0 || lines <= size.MaxLines {
+				scored.Factors = append(scored.Factors, ScoreFactor{Name: fmt.Sprintf("size %d lines", lines), Value: size.Multiplier})
+				points *= size.Multiplier
+				break
+			}
+		}
+	}
+	scored.Points = points
+	return scored
+}
+
+func (s *Scoring) tier(owner, repo string) (ScoreTier, bool) {
+	for _, t := range s.Tiers {
+		if containsFold(t.Orgs, owner) || containsFold(t.Repos, owner+"/"+repo) {
+			return t, true
+		}
+	}
+	return ScoreTier{}, false
+}
+
+// fetchPRSizes looks up the lines each PR adds and removes, keyed by URL.
+func fetchPRSizes(ctx context.Context, prs []Issue, token string) (map[string]int, error) {
+	sizes := make(map[string]int)
+	for _, pr := range prs {
+		var pull struct {
+			Additions int `json:"additions"`
+			Deletions int `json:"deletions"`
+		}
+		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", url.PathEscape(pr.Owner), url.PathEscape(pr.Repo), pr.Number)
+		if err := getGitHubJSON(ctx, apiURL, token, &pull); err != nil {
+			return nil, fmt.Errorf("fetching the size of %s: %w", pr.URL, err)
+		}
+		sizes[pr.URL] = pull.Additions + pull.Deletions
+	}
+	return sizes, nil
+}
+
+// scoredActivity scores every activity of every user.
+func (d *Dataset) scoredActivity() map[string][]ScoredActivity {
+	scored := make(map[string][]ScoredActivity)
+	for user, activities := range d.Activity() {
+		data := d.user(user)
+		merged := make(map[string]bool)
+		for _, pr := range data.ClosedPRs {
+			if pr.Merged() {
+				merged[pr.URL] = true
+			}
+		}
+		for _, a := range activities {
+			kind := a.Action
+			if kind == "closed_pr" && merged[a.URL] {
+				kind = "merged_pr"
+			}
+			lines := -1
+			if size, ok := data.PRSizes[a.URL]; ok && (kind == "opened_pr" || kind == "closed_pr" || kind == "merged_pr") {
+				lines = size
+			}
+			scored[user] = append(scored[user], d.scoring.score(a, kind, lines))
+		}
+	}
+	return scored
+}
+
+// leaderboard ranks the users by the points of their activity that keep allows.
+// Users with no points are left out. withBreakdown keeps every scored activity on the entries.
+func (d *Dataset) leaderboard(scored map[string][]ScoredActivity, keep func(ScoredActivity) bool, withBreakdown bool) []LeaderboardEntry {
+	var entries []LeaderboardEntry
+	for _, user := range d.Users {
+		person := d.person(user)
+		entry := LeaderboardEntry{User: user, Name: person.DisplayName(), Avatar: person.Avatar}
+		kinds := make(map[string]*KindScore)
+		for _, s := range scored[user] {
+			if !keep(s) {
+				continue
+			}
+			entry.Points += s.Points
+			k := kinds[s.Kind]
+			if k == nil {
+				k = &KindScore{Kind: s.Kind}
+				kinds[s.Kind] = k
+			}
+			k.Count++
+			k.Points += s.Points
+			if withBreakdown {
+				entry.Breakdown = append(entry.Breakdown, s)
+			}
+		}
+		if entry.Points <= 0 {
+			continue
+		}
+		for _, kind := range sortedKeys(kinds) {
+			entry.Kinds = append(entry.Kinds, *kinds[kind])
+		}
+		sort.SliceStable(entry.Kinds, func(i, j int) bool {
+			return entry.Kinds[i].Points > entry.Kinds[j].Points
+		})
+		sort.SliceStable(entry.Breakdown, func(i, j int) bool {
+			return entry.Breakdown[i].Points > entry.Breakdown[j].Points
+		})
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Points != entries[j].Points {
+			return entries[i].Points > entries[j].Points
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	// Ties share a rank and the next rank skips past them.
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Points == entries[i-1].Points {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return entries
+}
+
+// LeaderboardReport ranks users over the rolling window, with each score
+// broken down, and for every month with activity, newest first.
+func (d *Dataset) LeaderboardReport() LeaderboardReport {
+	report := LeaderboardReport{RollingDays: d.scoring.RollingDays, Weights: d.scoring.Weights}
+	scored := d.scoredActivity()
+
+	since := d.FetchedAt.AddDate(0, 0, -d.scoring.RollingDays)
+	report.Rolling = d.leaderboard(scored, func(s ScoredActivity) bool {
+		return !s.Timestamp.Before(since)
+	}, true)
+
+	monthSet := make(map[string]bool)
+	for _, activities := range scored {
+		for _, s := range activities {
+			monthSet[s.Timestamp.Format("2006-01")] = true
+		}
+	}
+	months := sortedKeys(monthSet)
+	for i := len(months) - 1; i >= 0; i-- {
+		month := months[i]
+		report.Months = append(report.Months, LeaderboardMonth{
+			Month: month,
+			Entries: d.leaderboard(scored, func(s ScoredActivity) bool {
+				return s.Timestamp.Format("2006-01") == month
+			}, false),
+		})
+	}
+	return report
+}
+
+// formatPoints shows points with at most one decimal, e.g. "12" or "2.5".
+func formatPoints(p float64) string {
+	return strconv.FormatFloat(math.Round(p*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,48 @@
+package oslib
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeCountsWhenItHappened(t *testing.T) {
+	merged := Issue{
+		Title:       "Add a feature",
+		URL:         "https://github.com/o/r/pull/1",
+		Repo:        "r",
+		Owner:       "o",
+		CreatedAt:   "2026-01-10T09:00:00Z",
+		ClosedAt:    "2026-03-05T12:00:00Z",
+		PullRequest: &PullRequestRef{MergedAt: "2026-03-05T12:00:00Z"},
+	}
+	closed := Issue{
+		Title:       "Abandoned",
+		URL:         "https://github.com/o/r/pull/2",
+		Repo:        "r",
+		Owner:       "o",
+		CreatedAt:   "2026-01-11T09:00:00Z",
+		ClosedAt:    "2026-02-20T12:00:00Z",
+		PullRequest: &PullRequestRef{},
+	}
+	d := &Dataset{
+		FetchedAt: time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC),
+		Users:     []string{"octocat"},
+		UserData:  map[string]*UserData{"octocat": {ClosedPRs: []Issue{merged, closed}}},
+		scoring:   (&Config{Scoring: &Scoring{RollingDays: 7}}).scoring(),
+	}
+
+	report := d.LeaderboardReport()
+	if len(report.Rolling) != 1 || report.Rolling[0].Points != defaultWeights["merged_pr"] {
+		t.Errorf("rolling leaderboard = %+v, want the merge from yesterday only", report.Rolling)
+	}
+	months := make(map[string]float64)
+	for _, m := range report.Months {
+		for _, e := range m.Entries {
+			months[m.Month] += e.Points
+		}
+	}
+	want := map[string]float64{"2026-03": defaultWeights["merged_pr"], "2026-02": defaultWeights["closed_pr"]}
+	if len(months) != len(want) || months["2026-03"] != want["2026-03"] || months["2026-02"] != want["2026-02"] {
+		t.Errorf("points per month = %v, want %v", months, want)
+	}
+}
@@ -0,0 +1,432 @@
+package oslib
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server hosts every report over HTTP and regenerates them in the background.
+// Pages are rendered into a fresh directory on each refresh and swapped in
+// only when the whole render succeeded, so readers always see the last good one.
+type Server struct {
+	config   *Config
+	users    []string
+	token    string
+	delay    time.Duration
+	interval time.Duration
+	trigger  chan struct{}
+	// webhookSecret enables POST /webhook when set.
+	webhookSecret string
+	// refreshToken enables POST /refresh when set, for callers presenting it.
+	refreshToken string
+
+	// updateMu serialises renders, so webhook changes and refreshes never swap in over each other.
+	updateMu sync.Mutex
+	// unrendered is the served dataset with the webhook changes not yet
+	// rendered, or nil when there are none. It is guarded by updateMu.
+	unrendered *Dataset
+
+	mu      sync.RWMutex
+	dir     string
+	dataset *Dataset
+	// apiData is dataset narrowed by the filter profile the JSON API applies.
+	apiData     *Dataset
+	lastRefresh time.Time
+	lastErr     error
+	refreshing  bool
+	// lastStart is when the last refresh began, successful or not; it throttles POST /refresh.
+	lastStart time.Time
+	// refreshFailures and lastFetchDuration are exposed on /metrics.
+	refreshFailures   int
+	lastFetchDuration time.Duration
+	// pending holds webhook changes received while a refresh was fetching,
+	// replayed onto the new dataset so they are not lost when it is swapped in.
+	pending []func(d *Dataset)
+}
+
+// RefreshTokenEnv names the environment variable holding the token POST /refresh
+// requires. Without it the refresh endpoint is disabled.
+const RefreshTokenEnv = "TRACKER_REFRESH_TOKEN"
+
+func NewServer(config *Config, users []string, token string, delay, interval time.Duration, webhookSecret, refreshToken string) *Server {
+	return &Server{
+		config:        config,
+		users:         users,
+		token:         token,
+		delay:         delay,
+		interval:      interval,
+		trigger:       make(chan struct{}, 1),
+		webhookSecret: webhookSecret,
+		refreshToken:  refreshToken,
+	}
+}
+
+// Run refreshes right away, then every interval or whenever a refresh is requested, until ctx is done.
+func (s *Server) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Refresh failed, still serving the previous reports: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			os.RemoveAll(s.dir)
+			s.mu.Unlock()
+			return
+		case <-ticker.C:
+		case <-s.trigger:
+		}
+	}
+}
+
+// RequestRefresh asks Run for a refresh without waiting for it. Requests made while one is pending are merged.
+func (s *Server) RequestRefresh() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Refresh fetches the dataset, renders every report and swaps them in.
+func (s *Server) Refresh(ctx context.Context) error {
+	s.setRefreshing(true)
+	defer s.setRefreshing(false)
+
+	start := time.Now()
+	d, err := FetchDataset(ctx, s.config, s.users, s.token, s.delay, Reports)
+	fetchDuration := time.Since(start)
+	if err == nil {
+		s.updateMu.Lock()
+		s.mu.Lock()
+		pending := s.pending
+		s.pending = nil
+		s.mu.Unlock()
+		for _, change := range pending {
+			change(d)
+		}
+		err = s.render(d)
+		if err == nil {
+			// The changes waiting to be rendered are in d too, on fresher data.
+			s.unrendered = nil
+		}
+		s.updateMu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+	if err != nil {
+		s.refreshFailures++
+		return err
+	}
+	s.lastRefresh = time.Now()
+	s.lastFetchDuration = fetchDuration
+	log.Printf("Reports refreshed in %s", time.Since(start).Round(time.Second))
+	return nil
+}
+
+func (s *Server) render(d *Dataset) error {
+	dir, err := os.MkdirTemp("", "open-source-tracker-")
+	if err != nil {
+		return err
+	}
+	if err := s.carryOverState(dir); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	if err := RenderReports(d, s.config, Reports, Output{Renderer: htmlRenderer{}, Dir: dir}); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	api := d.filtered(s.config.apiFilter())
+
+	s.mu.Lock()
+	old := s.dir
+	s.dir = dir
+	s.dataset = d
+	s.apiData = api
+	s.mu.Unlock()
+
+	// Files already opened from the old directory stay readable after removal.
+	os.RemoveAll(old)
+	return nil
+}
+
+// carryOverState copies what the last render remembered about its run, such as
+// the tracked users, into the directory of the next one.
+func (s *Server) carryOverState(dir string) error {
+	s.mu.RLock()
+	old := s.dir
+	s.mu.RUnlock()
+	if old == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(old, trackedUsersFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, trackedUsersFile), data, 0644)
+}
+
+// renderDelay batches changes arriving close together, such as a busy
+// comment stream, into one render.
+const renderDelay = 10 * time.Second
+
+// update applies change to a copy of the served dataset and schedules a
+// render of the result. Further changes before the render join the same copy.
+func (s *Server) update(change func(d *Dataset)) {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	s.mu.Lock()
+	current := s.dataset
+	if s.refreshing {
+		s.pending = append(s.pending, change)
+	}
+	s.mu.Unlock()
+
+	if s.unrendered == nil {
+		if current == nil {
+			return
+		}
+		s.unrendered = current.Clone()
+		time.AfterFunc(renderDelay, s.renderUpdates)
+	}
+	change(s.unrendered)
+}
+
+// renderUpdates serves the changes update collected, unless a refresh has replaced them since.
+func (s *Server) renderUpdates() {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	d := s.unrendered
+	s.unrendered = nil
+	if d == nil {
+		return
+	}
+	if err := s.render(d); err != nil {
+		log.Printf("Error rendering webhook changes: %v", err)
+	}
+}
+
+func (s *Server) setRefreshing(refreshing bool) {
+	s.mu.Lock()
+	s.refreshing = refreshing
+	if refreshing {
+		s.pending = nil
+		s.lastStart = time.Now()
+	}
+	s.mu.Unlock()
+}
+
+// minRefreshInterval spaces out refreshes requested over HTTP, since each one
+// costs hundreds of GitHub API calls.
+const minRefreshInterval = 5 * time.Minute
+
+// refreshWait is how long POST /refresh has to wait: until the running
+// refresh is done, or minRefreshInterval has passed since the last one began.
+func (s *Server) refreshWait() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.refreshing {
+		return minRefreshInterval
+	}
+	if s.lastStart.IsZero() {
+		return 0
+	}
+	return max(minRefreshInterval-time.Since(s.lastStart), 0)
+}
+
+// Dataset is the data behind the reports currently served, or nil before the first refresh.
+func (s *Server) Dataset() *Dataset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dataset
+}
+
+func (s *Server) apiDataset() *Dataset {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.apiData
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("POST /refresh", s.handleRefresh)
+	mux.HandleFunc("POST /webhook", s.handleWebhook)
+	mux.HandleFunc("GET /", s.handleReport)
+	s.registerAPI(mux)
+	return mux
+}
+
+type serverStatus struct {
+	Refreshing   bool           `json:"refreshing"`
+	LastRefresh  time.Time      `json:"last_refresh"`
+	LastError    string         `json:"last_error,omitempty"`
+	Labels       []LabelSummary `json:"-"`
+	HasTeams     bool           `json:"-"`
+	HasScoring   bool           `json:"-"`
+	HasCycleTime bool           `json:"-"`
+	CanRefresh   bool           `json:"-"`
+}
+
+func (s *Server) status() serverStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := serverStatus{Refreshing: s.refreshing, LastRefresh: s.lastRefresh, CanRefresh: s.refreshToken != ""}
+	if s.lastErr != nil {
+		status.LastError = s.lastErr.Error()
+	}
+	if s.dataset != nil {
+		status.HasTeams = len(s.dataset.Teams) > 0
+		status.HasScoring = s.dataset.scoring != nil
+		status.HasCycleTime = s.dataset.cycleTime
+		for _, l := range s.dataset.Labels {
+			status.Labels = append(status.Labels, l.summary())
+		}
+	}
+	return status
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := serverIndexTemplate.Execute(w, s.status()); err != nil {
+		log.Printf("Error rendering index: %v", err)
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.status())
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if s.refreshToken == "" {
+		http.Error(w, "refresh is not enabled", http.StatusNotFound)
+		return
+	}
+	if !validRefreshToken(s.refreshToken, r) {
+		http.Error(w, "invalid refresh token", http.StatusUnauthorized)
+		return
+	}
+	wait := s.refreshWait()
+	if wait == 0 {
+		s.RequestRefresh()
+	}
+	if r.Header.Get("Accept") == "application/json" {
+		if wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Round(time.Second).Seconds())))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// validRefreshToken reports whether r carries want as a bearer token, or in
+// the token field of the form on the index page.
+func validRefreshToken(want string, r *http.Request) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		got = r.PostFormValue("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// servedExts are the files a render produces for readers. Anything else in
+// the render directory, such as tracked_users.json, stays private.
+var servedExts = []string{".html", ".atom", ".feed.json"}
+
+// servable reports whether a request path names a report page or feed.
+func servable(urlPath string) bool {
+	name := strings.TrimPrefix(urlPath, "/")
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return false
+	}
+	for _, ext := range servedExts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	dir := s.dir
+	s.mu.RUnlock()
+
+	if !servable(r.URL.Path) {
+		http.NotFound(w, r)
+		return
+	}
+	if dir == "" {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "Reports are still being generated, try again shortly.", http.StatusServiceUnavailable)
+		return
+	}
+	http.FileServer(http.Dir(dir)).ServeHTTP(w, r)
+}
+
+var serverIndexTemplate = template.Must(template.New("serverIndex").Parse(`
+<!DOCTYPE html>
+<html>
+<head>
+	<title>Open Source Tracker</title>
+	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
+</head>
+<body class="container mt-5">
+	<h1 class="mb-4">Open Source Tracker</h1>
+	<ul class="list-group mb-4">
+		<li class="list-group-item"><a href="user_dashboard.html">User Issues</a></li>
+		<li class="list-group-item"><a href="team_achievements.html">Monthly Report</a></li>
+		<li class="list-group-item"><a href="kubernetes_contributions.html">Kubernetes Contributions</a></li>
+		{{ if .HasTeams }}<li class="list-group-item"><a href="teams.html">Teams</a></li>{{ end }}
+		{{ if .HasCycleTime }}<li class="list-group-item"><a href="pr_cycle_time.html">PR Cycle Time</a></li>{{ end }}
+		<li class="list-group-item"><a href="needs_attention.html">Needs Attention</a></li>
+		{{ if .HasScoring }}<li class="list-group-item"><a href="leaderboard.html">Leaderboard</a></li>{{ end }}
+		<li class="list-group-item"><a href="labels.html">All Issue Labels</a></li>
+		{{ range .Labels }}
+		<li class="list-group-item"><a href="{{ .Page }}.html">{{ .Title }}</a> <span class="badge bg-secondary">{{ .Count }}</span></li>
+		{{ end }}
+	</ul>
+	<p class="text-muted">
+		{{ if .LastRefresh.IsZero }}Reports have not been generated yet.{{ else }}Last refreshed {{ .LastRefresh.Format "2006-01-02 15:04 MST" }}.{{ end }}
+		{{ if .Refreshing }}A refresh is in progress.{{ end }}
+	</p>
+	{{ if .LastError }}<div class="alert alert-warning">Last refresh failed: {{ .LastError }}</div>{{ end }}
+	{{ if .CanRefresh }}
+	<form method="post" action="refresh" class="d-flex gap-2">
+		<input class="form-control w-auto" type="password" name="token" placeholder="Refresh token" required>
+		<button class="btn btn-primary" type="submit">Refresh now</button>
+	</form>
+	{{ end }}
+</body>
+</html>
+`))
@@ -0,0 +1,74 @@
+package oslib
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServable(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/user_dashboard.html", true},
+		{"/good_first_issues.atom", true},
+		{"/good_first_issues.feed.json", true},
+		{"/", false},
+		{"/tracked_users.json", false},
+		{"/user_dashboard.json", false},
+		{"/.hidden.html", false},
+		{"/sub/page.html", false},
+		{"/../page.html", false},
+		{`/a\b.html`, false},
+	}
+	for _, tt := range tests {
+		if got := servable(tt.path); got != tt.want {
+			t.Errorf("servable(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRefreshNeedsToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		form   string
+		want   int
+	}{
+		{"disabled", "", "Bearer s3cret", "", http.StatusNotFound},
+		{"no token", "s3cret", "", "", http.StatusUnauthorized},
+		{"wrong bearer", "s3cret", "Bearer nope", "", http.StatusUnauthorized},
+		{"bearer", "s3cret", "Bearer s3cret", "", http.StatusAccepted},
+		{"index form", "s3cret", "", "s3cret", http.StatusSeeOther},
+		{"wrong form", "s3cret", "", "nope", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(&Config{}, nil, "", 0, time.Hour, "", tt.token)
+			var req *http.Request
+			if tt.form != "" {
+				req = httptest.NewRequest("POST", "/refresh", strings.NewReader(url.Values{"token": {tt.form}}.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			} else {
+				req = httptest.NewRequest("POST", "/refresh", nil)
+				req.Header.Set("Accept", "application/json")
+			}
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if requested := len(s.trigger) == 1; requested != (tt.want < 400) {
+				t.Errorf("refresh requested = %v with status %d", requested, rec.Code)
+			}
+		})
+	}
+}
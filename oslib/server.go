@@ -1,471 +1,131 @@
 package oslib
 
 import (
-	"bytes"
+	"context"
+	"fmt"
 	"log"
-	"os"
-	"sort"
-	"strings"
-	"text/template"
 	"time"
 )
 
-func GenerateIssuesReport(orgs []string, token string, labels []string) {
-	for _, label := range labels {
-		var Issues []Issue
-
-		for _, org := range orgs {
-			Issues = append(Issues, FetchIssues(org, token, label)...)
-		}
-
-		sort.Slice(Issues, func(i, j int) bool {
-			return Issues[i].CreatedAt > Issues[j].CreatedAt
-		})
-
-		tmpl := template.Must(template.New("goodFirstIssues").Parse(`
-    <!DOCTYPE html>
-    <html>
-    <head>
-        <title>Issues</title>
-        <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
-    </head>
-    <body class="container mt-5">
-        <h1 class="mb-4">Issues</h1>
-        {{ if . }}
-        <table class="table table-striped">
-            <thead>
-                <tr>
-                    <th>Title</th>
-                    <th>Repository</th>
-                    <th>URL</th>
-                    <th>Created At</th>
-                </tr>
-            </thead>
-            <tbody>
-                {{ range . }}
-                <tr>
-                    <td>{{ .Title }}</td>
-                    <td>{{ .Repo }}</td>
-                    <td><a href="{{ .URL }}" target="_blank">{{ .URL }}</a></td>
-                    <td>{{ .CreatedAt }}</td>
-                </tr>
-                {{ end }}
-            </tbody>
-        </table>
-        {{ else }}
-        <p>No issues found</p>
-        {{ end }}
-    </body>
-    </html>
-    `))
-
-		var buf bytes.Buffer
-		if err := tmpl.Execute(&buf, Issues); err != nil {
-			log.Fatalf("Error rendering template: %v", err)
-		}
-
-		var outputFile string
-		if label == "good+first+issue" {
-			outputFile = "docs/good_first_issues.html"
-		} else if label == "help+wanted" {
-			outputFile = "docs/help_wanted.html"
-		}
-
-		if err := os.WriteFile(outputFile, buf.Bytes(), 0644); err != nil {
-			log.Fatalf("Error saving HTML file (%s): %v", outputFile, err)
-		}
-
-		log.Println("HTML report is generated")
+// Generate fetches the data needed by reports once and renders each of them to out.
+func Generate(config *Config, users []string, token string, delay time.Duration, reports []string, out Output) {
+	d, err := FetchDataset(context.Background(), config, users, token, delay, reports)
+	if err != nil {
+		log.Fatalf("Error fetching data: %v", err)
 	}
-}
-func GenerateReport(users []string, token string) {
-	data := make(map[string]map[string][]Issue)
-	for i, user := range users {
-		data[user] = map[string][]Issue{
-			"assigned_issues": FetchAssignedIssues(user, token),
-			"created_issues":  FetchCreatedIssues(user, token),
-			"open_prs":        FetchOpenPRs(user, token),
-			"closed_prs":      FetchClosedPRs(user, token),
-		}
-		if i < len(users)-1 {
-			log.Printf("Sleeping for 30 seconds to avoid rate-limiting")
-			time.Sleep(30 * time.Second)
-		}
-
+	if err = RenderReports(d, config, reports, out); err != nil {
+		log.Fatalf("Error saving report: %v", err)
 	}
-
-	tmpl := template.Must(template.New("dashboard").Parse(`
-	<!DOCTYPE html>
-	<html>
-	<head>
-		<title>GitHub Dashboard</title>
-		<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
-		<script src="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/js/bootstrap.bundle.min.js"></script>
-		<style>
-			h3 { font-size: 1.25rem; font-weight: bold; background-color: #f8f9fa; padding: 0.5rem; border-radius: 0.25rem; }
-			.table td, .table th { width: auto; text-align: left; }
-		</style>
-	</head>
-	<body class="container mt-5">
-		<h1 class="mb-4">GitHub Dashboard</h1>
-		<div class="accordion" id="usersAccordion">
-			{{ range $user, $data := . }}
-			<div class="accordion-item">
-				<h2 class="accordion-header" id="heading-{{ $user }}">
-					<button class="accordion-button collapsed" type="button" data-bs-toggle="collapse" data-bs-target="#collapse-{{ $user }}" aria-expanded="false" aria-controls="collapse-{{ $user }}">
-						<span style="font-size: 1.5rem; font-weight: bold;">{{ $user }}</span>
-					</button>
-				</h2>
-				<div id="collapse-{{ $user }}" class="accordion-collapse collapse" aria-labelledby="heading-{{ $user }}" data-bs-parent="#usersAccordion">
-					<div class="accordion-body">
-						<h3 style="background-color: #d1e7dd;">Assigned Issues</h3>
-						{{ if $data.assigned_issues }}
-						<table class="table table-striped">
-							<thead>
-								<tr><th>Title</th><th>Repository</th><th>URL</th><th>Updated At</th></tr>
-							</thead>
-							<tbody>
-								{{ range $issue := $data.assigned_issues }}
-								<tr>
-									<td>{{ $issue.Title }}</td>
-									<td>{{ $issue.Repo }}</td>
-									<td><a href="{{ $issue.URL }}" target="_blank">{{ $issue.URL }}</a></td>
-									<td>{{ $issue.UpdatedAt }}</td>
-								</tr>
-								{{ end }}
-							</tbody>
-						</table>
-						{{ else }}<p>No assigned issues</p>{{ end }}
-
-						<h3 style="background-color: #ffeeba;">Created Issues</h3>
-						{{ if $data.created_issues }}
-						<table class="table table-striped">
-							<thead>
-								<tr><th>Title</th><th>Repository</th><th>URL</th><th>Updated At</th></tr>
-							</thead>
-							<tbody>
-								{{ range $issue := $data.created_issues }}
-								<tr>
-									<td>{{ $issue.Title }}</td>
-									<td>{{ $issue.Repo }}</td>
-									<td><a href="{{ $issue.URL }}" target="_blank">{{ $issue.URL }}</a></td>
-									<td>{{ $issue.UpdatedAt }}</td>
-								</tr>
-								{{ end }}
-							</tbody>
-						</table>
-						{{ else }}<p>No created issues</p>{{ end }}
-
-						<h3 style="background-color: #f8d7da;">Open PRs</h3>
-						{{ if $data.open_prs }}
-						<table class="table table-striped">
-							<thead>
-								<tr><th>Title</th><th>Repository</th><th>URL</th><th>Updated At</th></tr>
-							</thead>
-							<tbody>
-								{{ range $issue := $data.open_prs }}
-								<tr>
-									<td>{{ $issue.Title }}</td>
-									<td>{{ $issue.Repo }}</td>
-									<td><a href="{{ $issue.URL }}" target="_blank">{{ $issue.URL }}</a></td>
-									<td>{{ $issue.UpdatedAt }}</td>
-								</tr>
-								{{ end }}
-							</tbody>
-						</table>
-						{{ else }}<p>No open PRs</p>{{ end }}
-						<h3 style="background-color: #f8d7da;">Closed PRs (past 1 year)</h3>
-						{{ if $data.closed_prs }}
-						<table class="table table-striped">
-							<thead>
-								<tr><th>Title</th><th>Repository</th><th>URL</th><th>Updated At</th></tr>
-							</thead>
-							<tbody>
-								{{ range $issue := $data.closed_prs }}
-								<tr>
-									<td>{{ $issue.Title }}</td>
-									<td>{{ $issue.Repo }}</td>
-									<td><a href="{{ $issue.URL }}" target="_blank">{{ $issue.URL }}</a></td>
-									<td>{{ $issue.UpdatedAt }}</td>
-								</tr>
-								{{ end }}
-							</tbody>
-						</table>
-						{{ else }}<p>No closed PRs</p>{{ end }}
-					</div>
-				</div>
-			</div>
-			{{ end }}
-		</div>
-
-		</tbody>
-	</table>
-</div>
-	</body>
-	</html>
-	`))
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		log.Fatalf("Error rendering template: %v", err)
-	}
-
-	if err := os.WriteFile("docs/user_dashboard.html", buf.Bytes(), 0644); err != nil {
-		log.Fatalf("Error saving HTML file: %v", err)
-	}
-
-	log.Println("HTML report generated and saved to dashboard.html")
 }
 
-func GenerateTeamAchievements(users []string, token string) {
-	activityMap := FetchMonthlyActivity(users, token)
-	groupedData, months := GroupMonthlyActivity(activityMap)
-
-	funcMap := template.FuncMap{
-		"formatDate": func(t time.Time) string {
-			return t.Format("Jan 2")
-		},
-		"badgeClass": func(action string) string {
-			switch action {
-			case "created_issue_open":
-				return "primary"
-			case "created_issue_closed":
-				return "info"
-			case "opened_pr":
-				return "warning"
-			case "closed_pr":
-				return "success"
-			default:
-				return "secondary"
+// RenderReports renders reports from an already fetched dataset, each
+// narrowed by the filter profile the config gives it.
+func RenderReports(all *Dataset, config *Config, reports []string, out Output) error {
+	for _, report := range reports {
+		d := all.filtered(config.filterFor(report))
+		var err error
+		switch report {
+		case PageDashboard:
+			err = writePage(out, Page{Kind: PageDashboard, Name: "user_dashboard", Data: d.DashboardReport()})
+			for _, g := range d.teamGroups() {
+				if err == nil {
+					err = writePage(out, Page{Kind: PageDashboard, Name: g.page(teamDashboardPage), Data: d.teamDashboardReport(g)})
+				}
 			}
-		},
-		"actionLabel": func(action string) string {
-			switch action {
-			case "created_issue_open":
-				return "Created Issue (Open)"
-			case "created_issue_closed":
-				return "Created Issue (Closed)"
-			case "opened_pr":
-				return "Opened PR"
-			case "closed_pr":
-				return "Closed PR"
-			default:
-				return action
+			for _, class := range d.classNames() {
+				if err == nil {
+					err = writePage(out, Page{Kind: PageDashboard, Name: classDashboardPage + class, Data: d.classDashboardReport(class)})
+				}
 			}
-		},
-		"filterByAction": func(acts []Activity, action string) []Activity {
-			var filtered []Activity
-			for _, a := range acts {
-				if a.Action == action {
-					filtered = append(filtered, a)
+		case PageIssues:
+			err = writeIssuesReport(d, config, out)
+		case PageAchievements:
+			err = writePage(out, Page{Kind: PageAchievements, Name: "team_achievements", Data: d.AchievementsReport()})
+			for _, g := range d.teamGroups() {
+				if err == nil {
+					err = writePage(out, Page{Kind: PageAchievements, Name: g.page(teamAchievementsPage), Data: d.teamAchievementsReport(g)})
 				}
 			}
-			return filtered
-		},
-		"escapeID": func(s string) string {
-			safe := strings.ReplaceAll(s, "@", "_")
-			safe = strings.ReplaceAll(safe, ".", "_")
-			safe = strings.ReplaceAll(safe, "/", "_")
-			return safe
-		},
-	}
-
-	tmpl := template.Must(template.New("teamAchievements").Funcs(funcMap).Parse(`
-<!DOCTYPE html>
-<html>
-<head>
-	<title>Team Achievements</title>
-	<meta charset="utf-8">
-	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
-	<script src="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/js/bootstrap.bundle.min.js"></script>
-</head>
-<body class="container mt-5">
-	<h1 class="mb-4">Team Achievements by Month</h1>
-
-	{{ range .Months }}
-		{{ $month := . }}
-		<h2 class="mt-4">{{ $month }}</h2>
-
-		{{ range $user, $activities := index $.Data $month }}
-			<div class="card mb-2">
-				<div class="card-header">
-					<h5 class="mb-0">
-						<button class="btn btn-link text-decoration-none" data-bs-toggle="collapse" data-bs-target="#collapse-{{ $month }}-{{ $user | escapeID }}" aria-expanded="false" aria-controls="collapse-{{ $month }}-{{ $user | escapeID }}">
-							{{ $user }}
-						</button>
-					</h5>
-					<div class="mt-2">
-						<span class="badge bg-primary">Open Issues: {{ len (filterByAction $activities "created_issue_open") }}</span>
-						<span class="badge bg-info text-dark">Closed Issues: {{ len (filterByAction $activities "created_issue_closed") }}</span>
-						<span class="badge bg-warning text-dark">Opened PRs: {{ len (filterByAction $activities "opened_pr") }}</span>
-						<span class="badge bg-success">Closed PRs: {{ len (filterByAction $activities "closed_pr") }}</span>
-					</div>
-				</div>
-				<div id="collapse-{{ $month }}-{{ $user | escapeID }}" class="collapse">
-					<ul class="list-group list-group-flush">
-						{{ range $a := $activities }}
-						<li class="list-group-item">
-							<span class="badge bg-{{ badgeClass $a.Action }}">{{ actionLabel $a.Action }}</span>
-							<a href="{{ $a.URL }}" target="_blank">{{ $a.Title }}</a>
-							<span class="text-muted">in {{ $a.Repo }} on {{ formatDate $a.Timestamp }}</span>
-						</li>
-						{{ end }}
-					</ul>
-				</div>
-			</div>
-		{{ end }}
-
-	{{ end }}
-</body>
-</html>
-`))
-
-	data := struct {
-		Data   map[string]map[string][]Activity
-		Months []string
-	}{
-		Data:   groupedData,
-		Months: months,
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		log.Fatalf("Error rendering template: %v", err)
-	}
-
-	if err := os.WriteFile("docs/team_achievements.html", buf.Bytes(), 0644); err != nil {
-		log.Fatalf("Error saving HTML file: %v", err)
+			for _, class := range d.classNames() {
+				if err == nil {
+					err = writePage(out, Page{Kind: PageAchievements, Name: classAchievementsPage + class, Data: d.classAchievementsReport(class)})
+				}
+			}
+		case PageKubernetes:
+			err = writePage(out, Page{Kind: PageKubernetes, Name: "kubernetes_contributions", Data: d.KubernetesReport()})
+		case PageCycleTime:
+			if d.cycleTime {
+				err = writePage(out, Page{Kind: PageCycleTime, Name: "pr_cycle_time", Data: d.CycleTimeReport()})
+			}
+		case PageStale:
+			err = writePage(out, Page{Kind: PageStale, Name: "needs_attention", Data: d.StaleReport()})
+		case PageLeaderboard:
+			// Teams that turned scoring off get no ranking at all.
+			if d.scoring != nil {
+				err = writePage(out, Page{Kind: PageLeaderboard, Name: "leaderboard", Data: d.LeaderboardReport()})
+			}
+		case PageUserChanges:
+			err = writeUserChanges(d, out)
+		case PageTeams:
+			// Without teams there is nothing to summarise beyond the dashboard itself.
+			if len(d.Teams) > 0 {
+				err = writePage(out, Page{Kind: PageTeams, Name: "teams", Data: d.TeamsReport()})
+			}
+		default:
+			err = fmt.Errorf("unknown report %q", report)
+		}
+		if err != nil {
+			return err
+		}
 	}
-
-	log.Println("Team achievements dashboard generated: docs/team_achievements.html")
+	return nil
 }
 
-func GenerateKubernetesContributions(users []string, token string) {
-	data := FetchKubernetesPRs(users, token)
-
-	// Define the color palette (rotates if more repos than colors)
-	colors := []string{
-		"primary", "success", "info", "warning", "danger", "secondary", "dark",
+// writeIssuesReport writes a page and feeds per label, and the labels index linking them.
+func writeIssuesReport(d *Dataset, config *Config, out Output) error {
+	// Feeds need site_url for their IDs and links, and a directory to be written to.
+	index := LabelIndexReport{Feeds: config.SiteURL != "" && !out.Stdout()}
+	if config.SiteURL == "" && !out.Stdout() && len(d.Labels) > 0 {
+		log.Printf("Skipping label feeds: site_url is not set")
 	}
-
-	// Build a map: repoName -> assigned color index
-	repoColorMap := make(map[string]string)
-	colorIndex := 0
-
-	// Pass function to template for consistent repo color assignment
-	tmpl := template.Must(template.New("k8sRepoColor").Funcs(template.FuncMap{
-		"assignColor": func(repo string) string {
-			if color, exists := repoColorMap[repo]; exists {
-				return color
-			}
-			color := colors[colorIndex%len(colors)]
-			repoColorMap[repo] = color
-			colorIndex++
-			return color
-		},
-	}).Parse(`
-<!DOCTYPE html>
-<html>
-<head>
-	<title>Kubernetes PR Contributions</title>
-	<link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.0-alpha1/dist/css/bootstrap.min.css" rel="stylesheet">
-	<style>
-		body { font-size: 0.95rem; background-color: #f8f9fa; }
-		table td, table th { vertical-align: top; }
-		.pr-btn {
-			font-size: 0.75rem;
-			padding: 2px 6px;
-			border-radius: 0.4rem;
-			margin: 2px;
-			text-decoration: none;
-		}
-		.pr-btn:hover { opacity: 0.85; text-decoration: underline; }
-
-		.username-container {
-			position: relative;
-			display: inline-block;
-		}
-		.contributor-popup {
-			display: none;
-			position: absolute;
-			top: 25px;
-			left: 0;
-			z-index: 100;
-			width: 350px;
-			height: 480px;
-			border: 2px solid #ccc;
-			border-radius: 0.5rem;
-			overflow: hidden;
-			box-shadow: 0 4px 10px rgba(0,0,0,0.1);
-			background-color: white;
-		}
-		.username-container:hover .contributor-popup {
-			display: block;
-		}
-		.username-link {
-			color: #0d6efd;
-			text-decoration: none;
-			font-weight: 600;
-			cursor: pointer;
+	for _, l := range d.Labels {
+		issues := l.Free()
+		outputFile := l.Label.Slug()
+		title := l.Label.DisplayName()
+
+		report := IssuesReport{Label: l.Label.Name, Title: title, Issues: issues, LikelyTaken: l.Taken()}
+		if index.Feeds {
+			report.Feed = outputFile
 		}
-		.username-link:hover {
-			text-decoration: underline;
-			color: #084298;
+		if err := writePage(out, Page{Kind: PageIssues, Name: outputFile, Data: report}); err != nil {
+			return err
 		}
-	</style>
-</head>
-<body class="container mt-5">
-	<h1 class="mb-4">Kubernetes PR Contributions</h1>
-	<div class="mb-3">
-		<a href="user_dashboard.html" class="btn btn-secondary">← Back to Dashboard</a>
-	</div>
 
-	<table class="table table-bordered table-sm align-middle">
-		<thead class="table-light">
-			<tr>
-				<th style="width: 20%;">User</th>
-				<th>Pull Requests</th>
-			</tr>
-		</thead>
-		<tbody>
-			{{ range $user, $prs := . }}
-			<tr>
-				<td>
-					<div class="username-container">
-						<span class="username-link">{{ $user }}</span>
-						<div class="contributor-popup">
-							<iframe src="https://contribcard.clotributor.dev/{{ $user }}" 
-							        width="100%" height="100%" frameborder="0"></iframe>
-						</div>
-					</div>
-				</td>
-				<td>
-					{{ if $prs }}
-						{{ range $pr := $prs }}
-							<a href="{{ $pr.URL }}" target="_blank"
-							   class="btn btn-{{ assignColor $pr.Repo }} pr-btn"
-							   title="{{ $pr.Title }}">{{ $pr.Repo }}</a>
-						{{ end }}
-					{{ else }}
-						<em>No PRs</em>
-					{{ end }}
-				</td>
-			</tr>
-			{{ end }}
-		</tbody>
-	</table>
-</body>
-</html>
-`))
+		if index.Feeds {
+			if err := WriteFeeds(out.Dir, config.SiteURL, outputFile, title, issues); err != nil {
+				return fmt.Errorf("writing feeds for %s: %w", l.Label.Name, err)
+			}
+			if config.FeedsPerOrg {
+				for org, orgIssues := range l.ByOrg {
+					name := outputFile + "-" + org
+					if err := WriteFeeds(out.Dir, config.SiteURL, name, title+" in "+org, l.unclaimed(orgIssues)); err != nil {
+						return fmt.Errorf("writing feeds for %s in %s: %w", l.Label.Name, org, err)
+					}
+				}
+			}
+		}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		log.Fatalf("Error rendering Kubernetes contributions UI: %v", err)
+		index.Labels = append(index.Labels, l.summary())
 	}
 
-	if err := os.WriteFile("docs/kubernetes_contributions.html", buf.Bytes(), 0644); err != nil {
-		log.Fatalf("Error writing HTML file: %v", err)
-	}
+	return writePage(out, Page{Kind: PageLabelIndex, Name: "labels", Data: index})
+}
 
-	log.Println("Generated Kubernetes PR dashboard with consistent repo-based colors and hover popups: docs/kubernetes_contributions.html")
+func writePage(out Output, p Page) error {
+	path, err := out.Write(p)
+	if err != nil {
+		return err
+	}
+	log.Printf("Generated %s", path)
+	return nil
 }
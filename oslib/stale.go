@@ -0,0 +1,120 @@
+package oslib
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Staleness sets when an open PR or assigned issue needs attention. A zero threshold is not checked.
+type Staleness struct {
+	// DaysSinceUpdate flags items nothing happened to for this many days.
+	DaysSinceUpdate int `json:"days_since_update" yaml:"days_since_update"`
+	// DaysSinceComment flags items nobody but their author commented on or
+	// reviewed for this many days. It costs API calls per item, so it is off by default.
+	DaysSinceComment int `json:"days_since_comment" yaml:"days_since_comment"`
+}
+
+// defaultStaleness applies when the config has no stale section.
+var defaultStaleness = Staleness{DaysSinceUpdate: 30}
+
+func (c *Config) staleness() Staleness {
+	if c == nil || c.Stale == nil {
+		return defaultStaleness
+	}
+	return *c.Stale
+}
+
+// fetchLastReplies finds when someone other than its author last commented
+// on, or reviewed, each issue and PR. Items nobody replied to are left out.
+func fetchLastReplies(ctx context.Context, issues []Issue, token string) (map[string]string, error) {
+	replies := make(map[string]string)
+	for _, issue := range issues {
+		comments, err := fetchIssueComments(ctx, issue, "", token)
+		if err != nil {
+			return nil, fmt.Errorf("fetching comments of %s: %w", issue.URL, err)
+		}
+		for _, c := range comments {
+			if !strings.EqualFold(c.User.Login, issue.Author()) {
+				replies[issue.URL] = max(replies[issue.URL], c.CreatedAt)
+			}
+		}
+		if issue.PullRequest == nil {
+			continue
+		}
+		reviews, err := fetchPRReviews(ctx, issue, token)
+		if err != nil {
+			return nil, fmt.Errorf("fetching reviews of %s: %w", issue.URL, err)
+		}
+		for _, r := range reviews {
+			if !strings.EqualFold(r.User.Login, issue.Author()) && r.SubmittedAt != "" {
+				replies[issue.URL] = max(replies[issue.URL], r.SubmittedAt)
+			}
+		}
+	}
+	return replies, nil
+}
+
+// daysSince is how many whole days before now an RFC 3339 timestamp was.
+func daysSince(timestamp string, now time.Time) (int, bool) {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return 0, false
+	}
+	return int(now.Sub(t).Hours() / 24), true
+}
+
+// staleReason says why issue needs attention, or is empty when it does not.
+// age is the number of days since it was last updated. A threshold of N days
+// is reached on the Nth whole day.
+func (d *Dataset) staleReason(issue Issue, replies map[string]string) (age int, reason string) {
+	age, ok := daysSince(issue.UpdatedAt, d.FetchedAt)
+	if !ok {
+		return 0, ""
+	}
+	if d.stale.DaysSinceUpdate > 0 && age >= d.stale.DaysSinceUpdate {
+		return age, fmt.Sprintf("no update for %d days", age)
+	}
+	// Nobody is expected to review a draft, so only its updates count.
+	if d.stale.DaysSinceComment > 0 && replies != nil && !issue.Draft {
+		if last, ok := replies[issue.URL]; ok {
+			if days, _ := daysSince(last, d.FetchedAt); days >= d.stale.DaysSinceComment {
+				return age, fmt.Sprintf("no reply from others for %d days", days)
+			}
+		} else if days, ok := daysSince(issue.CreatedAt, d.FetchedAt); ok && days >= d.stale.DaysSinceComment {
+			return age, fmt.Sprintf("no reply from others in the %d days since it was opened", days)
+		}
+	}
+	return age, ""
+}
+
+// staleItems are the user's open PRs and assigned issues needing attention.
+func (d *Dataset) staleItems(user string) []StaleItem {
+	data := d.user(user)
+	person := d.person(user)
+	var items []StaleItem
+	add := func(kind string, issues []Issue) {
+		for _, issue := range issues {
+			if age, reason := d.staleReason(issue, data.LastReplies); reason != "" {
+				items = append(items, StaleItem{User: user, Name: person.DisplayName(), Kind: kind, Issue: issue, AgeDays: age, Reason: reason})
+			}
+		}
+	}
+	add("PR", data.OpenPRs)
+	add("Issue", data.AssignedIssues)
+	return items
+}
+
+// StaleReport lists every tracked user's stale items, oldest first.
+func (d *Dataset) StaleReport() StaleReport {
+	report := StaleReport{Thresholds: d.stale}
+	for _, user := range d.Users {
+		report.Items = append(report.Items, d.staleItems(user)...)
+	}
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		return report.Items[i].AgeDays > report.Items[j].AgeDays
+	})
+	return report
+}
@@ -0,0 +1,66 @@
+package oslib
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleReason(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days float64) string {
+		return now.Add(-time.Duration(days * 24 * float64(time.Hour))).Format(time.RFC3339)
+	}
+	const url = "https://github.com/o/r/pull/1"
+	pr := func(updated, created float64, draft bool) Issue {
+		return Issue{URL: url, UpdatedAt: daysAgo(updated), CreatedAt: daysAgo(created), Draft: draft, PullRequest: &PullRequestRef{}}
+	}
+	tests := []struct {
+		name    string
+		stale   Staleness
+		issue   Issue
+		replies map[string]string
+		want    string
+	}{
+		{"fresh", Staleness{DaysSinceUpdate: 30}, pr(1, 1, false), nil, ""},
+		{"a day short of the threshold", Staleness{DaysSinceUpdate: 30}, pr(29.9, 40, false), nil, ""},
+		{"exactly the threshold", Staleness{DaysSinceUpdate: 30}, pr(30, 40, false), nil, "no update for 30 days"},
+		{"zero threshold is off", Staleness{}, pr(400, 400, false), map[string]string{}, ""},
+		{"draft without updates", Staleness{DaysSinceUpdate: 30}, pr(31, 40, true), nil, "no update for 31 days"},
+		{"old reply", Staleness{DaysSinceComment: 14}, pr(1, 40, false), map[string]string{url: daysAgo(14)}, "no reply from others for 14 days"},
+		{"recent reply", Staleness{DaysSinceComment: 14}, pr(1, 40, false), map[string]string{url: daysAgo(13)}, ""},
+		{"never replied to", Staleness{DaysSinceComment: 14}, pr(1, 20, false), map[string]string{}, "no reply from others in the 20 days since it was opened"},
+		{"newly opened", Staleness{DaysSinceComment: 14}, pr(1, 2, false), map[string]string{}, ""},
+		{"draft never replied to", Staleness{DaysSinceComment: 14}, pr(1, 20, true), map[string]string{}, ""},
+		{"replies not fetched", Staleness{DaysSinceComment: 14}, pr(1, 20, false), nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Dataset{FetchedAt: now, stale: tt.stale}
+			if _, got := d.staleReason(tt.issue, tt.replies); got != tt.want {
+				t.Errorf("staleReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaleReportOldestFirst(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	updated := func(days int) string { return now.AddDate(0, 0, -days).Format(time.RFC3339) }
+	d := &Dataset{
+		FetchedAt: now,
+		Users:     []string{"alice", "bob"},
+		UserData: map[string]*UserData{
+			"alice": {OpenPRs: []Issue{{URL: "a1", UpdatedAt: updated(40)}, {URL: "a2", UpdatedAt: updated(2)}}},
+			"bob":   {AssignedIssues: []Issue{{URL: "b1", UpdatedAt: updated(90)}}},
+		},
+		stale: defaultStaleness,
+	}
+	var got []string
+	for _, item := range d.StaleReport().Items {
+		got = append(got, item.User+" "+item.Kind+" "+item.Issue.URL)
+	}
+	want := []string{"bob Issue b1", "alice PR a1"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("stale items = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,402 @@
+package oslib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is one problem found in a config file, with the line it is on.
+type ConfigError struct {
+	Line int
+	// Source is the environment variable the value came from, if it did not come from the file.
+	Source string
+	Path   string
+	Msg    string
+}
+
+// ConfigErrors collects every problem in a config so they can be fixed in one go.
+type ConfigErrors struct {
+	File   string
+	Errors []ConfigError
+}
+
+func (e *ConfigErrors) Len() int {
+	return len(e.Errors)
+}
+
+func (e *ConfigErrors) Error() string {
+	var lines []string
+	for _, ce := range e.Errors {
+		where := e.File
+		switch {
+		case ce.Source != "":
+			where = ce.Source
+		case ce.Line > 0:
+			where = fmt.Sprintf("%s:%d", e.File, ce.Line)
+		}
+		if ce.Path != "" {
+			where += ": " + ce.Path
+		}
+		lines = append(lines, where+": "+ce.Msg)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ConfigErrors) add(l configLocator, path []interface{}, format string, args ...interface{}) {
+	line, source := l.at(path...)
+	e.Errors = append(e.Errors, ConfigError{Line: line, Source: source, Path: formatPath(path), Msg: fmt.Sprintf(format, args...)})
+}
+
+func formatPath(path []interface{}) string {
+	var b strings.Builder
+	for _, p := range path {
+		switch p := p.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", p)
+		default:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			fmt.Fprint(&b, p)
+		}
+	}
+	return b.String()
+}
+
+// configLocator maps a config path such as ("labels", 2, "name") to the line it was read from.
+type configLocator struct {
+	doc *yaml.Node
+	// overridden maps top-level keys to the environment variable that replaced them.
+	overridden map[string]string
+}
+
+func (l configLocator) at(path ...interface{}) (line int, source string) {
+	if len(path) > 0 {
+		if key, ok := path[0].(string); ok && l.overridden[key] != "" {
+			return 0, l.overridden[key]
+		}
+	}
+
+	node := l.doc
+	if node == nil {
+		return 0, ""
+	}
+	line = node.Line
+	for _, p := range path {
+		var next *yaml.Node
+		switch p := p.(type) {
+		case string:
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == p {
+						next = node.Content[i+1]
+					}
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && p < len(node.Content) {
+				next = node.Content[p]
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+		line = node.Line
+	}
+	return line, ""
+}
+
+// checkKnownFields reports every mapping key that has no matching yaml tag in t.
+func checkKnownFields(node *yaml.Node, t reflect.Type, path string, errs *ConfigErrors) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := make(map[string]reflect.Type)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+			if name == "-" || !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			fields[name] = f.Type
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			fieldPath := key.Value
+			if path != "" {
+				fieldPath = path + "." + key.Value
+			}
+			ft, ok := fields[key.Value]
+			if !ok {
+				errs.Errors = append(errs.Errors, ConfigError{Line: key.Line, Path: fieldPath, Msg: "unknown field"})
+				continue
+			}
+			checkKnownFields(node.Content[i+1], ft, fieldPath, errs)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			checkKnownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkKnownFields(node.Content[i+1], t.Elem(), path+"."+node.Content[i].Value, errs)
+		}
+	}
+}
+
+// githubLogin matches user and org names GitHub accepts.
+var githubLogin = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{0,38}$`)
+
+// githubRepo matches "owner/name" repo references.
+var githubRepo = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{0,38}/[A-Za-z0-9_.-]+$`)
+
+func checkRepos(l configLocator, errs *ConfigErrors, path []interface{}, repos []string) {
+	for i, repo := range repos {
+		if !githubRepo.MatchString(repo) {
+			errs.add(l, append(append([]interface{}(nil), path...), i), "%q is not an owner/name repo", repo)
+		}
+	}
+}
+
+// githubTeam matches "org/team-slug" team references.
+var githubTeam = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{0,38}/[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+func (c *Config) validate(l configLocator, errs *ConfigErrors) {
+	if len(c.Users) == 0 && !c.hasTeamMembers() && !c.syncsGitHubTeams() {
+		errs.add(l, []interface{}{"users"}, "at least one user is required, under users, in a team or from github_teams")
+	}
+	c.validateUsers(l, errs)
+	checkLogins(l, errs, []interface{}{"orgs"}, c.Orgs)
+	checkLogins(l, errs, []interface{}{"exclude_users"}, c.ExcludeUsers)
+	for i, team := range c.GitHubTeams {
+		if !githubTeam.MatchString(team) {
+			errs.add(l, []interface{}{"github_teams", i}, "%q is not an org/team-slug name", team)
+		}
+	}
+
+	if len(c.Labels) > 0 && len(c.Orgs) == 0 {
+		errs.add(l, []interface{}{"orgs"}, "at least one org is required to search for label issues")
+	}
+	slugs := make(map[string]int)
+	for i, label := range c.Labels {
+		if strings.TrimSpace(label.Name) == "" {
+			errs.add(l, []interface{}{"labels", i}, "label name is required")
+			continue
+		}
+		slug := label.Slug()
+		switch {
+		case slug == "":
+			errs.add(l, []interface{}{"labels", i}, "%q has no letters or digits to name its page after; set file", label.Name)
+		case strings.ContainsAny(slug, `/\`) || strings.Contains(slug, ".."):
+			errs.add(l, []interface{}{"labels", i, "file"}, "%q must be a plain file name", slug)
+		}
+		if label.UpdatedWithinDays < 0 {
+			errs.add(l, []interface{}{"labels", i, "updated_within_days"}, "must not be negative")
+		}
+		if first, ok := slugs[slug]; ok {
+			errs.add(l, []interface{}{"labels", i}, "writes to %q, like labels[%d]; set a different file", slug, first)
+		} else {
+			slugs[slug] = i
+		}
+	}
+
+	teams := make(map[string]int)
+	for i, team := range c.Teams {
+		if strings.TrimSpace(team.Name) == "" {
+			errs.add(l, []interface{}{"teams", i}, "team name is required")
+			continue
+		}
+		slug := team.Slug()
+		if slug == "" {
+			errs.add(l, []interface{}{"teams", i, "name"}, "%q has no letters or digits to name its pages after", team.Name)
+		} else if first, ok := teams[slug]; ok {
+			errs.add(l, []interface{}{"teams", i, "name"}, "%q has the same pages as teams[%d]; rename one of them", team.Name, first)
+		} else {
+			teams[slug] = i
+		}
+		if len(team.Members) == 0 && len(team.Leads) == 0 && team.GitHubTeam == "" {
+			errs.add(l, []interface{}{"teams", i}, "%q has no members", team.Name)
+		}
+		if team.GitHubTeam != "" && !githubTeam.MatchString(team.GitHubTeam) {
+			errs.add(l, []interface{}{"teams", i, "github_team"}, "%q is not an org/team-slug name", team.GitHubTeam)
+		}
+		checkLogins(l, errs, []interface{}{"teams", i, "leads"}, team.Leads)
+		checkLogins(l, errs, []interface{}{"teams", i, "members"}, team.Members)
+	}
+
+	for _, name := range sortedKeys(c.Filters) {
+		f := c.Filters[name]
+		if name == NoFilter {
+			errs.add(l, []interface{}{"filters", name}, "%q means no filter and cannot be defined", name)
+		}
+		checkLogins(l, errs, []interface{}{"filters", name, "include_orgs"}, f.IncludeOrgs)
+		checkLogins(l, errs, []interface{}{"filters", name, "exclude_orgs"}, f.ExcludeOrgs)
+		checkRepos(l, errs, []interface{}{"filters", name, "include_repos"}, f.IncludeRepos)
+		checkRepos(l, errs, []interface{}{"filters", name, "exclude_repos"}, f.ExcludeRepos)
+	}
+	for _, report := range sortedKeys(c.ReportFilters) {
+		name := c.ReportFilters[report]
+		if !containsFold(Reports, report) {
+			errs.add(l, []interface{}{"report_filters", report}, "%q is not a report; use one of %s", report, strings.Join(Reports, ", "))
+		}
+		if _, ok := c.Filters[name]; !ok && name != NoFilter {
+			errs.add(l, []interface{}{"report_filters", report}, "filter %q is not defined under filters", name)
+		}
+	}
+
+	if c.Stale != nil {
+		if c.Stale.DaysSinceUpdate < 0 {
+			errs.add(l, []interface{}{"stale", "days_since_update"}, "must not be negative")
+		}
+		if c.Stale.DaysSinceComment < 0 {
+			errs.add(l, []interface{}{"stale", "days_since_comment"}, "must not be negative")
+		}
+	}
+
+	if c.Scoring != nil {
+		c.Scoring.validate(l, errs)
+	}
+	c.validateClasses(l, errs)
+
+	if c.SiteURL != "" {
+		u, err := url.Parse(c.SiteURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.add(l, []interface{}{"site_url"}, "%q is not an http(s) URL", c.SiteURL)
+		}
+	}
+}
+
+// validateUsers checks each user's fields and that no login, main or alias, belongs to two users.
+func (c *Config) validateUsers(l configLocator, errs *ConfigErrors) {
+	seen := make(map[string]string)
+	login := func(path []interface{}, login string) {
+		if !githubLogin.MatchString(login) {
+			errs.add(l, path, "%q is not a valid GitHub name", login)
+			return
+		}
+		if first, ok := seen[strings.ToLower(login)]; ok {
+			errs.add(l, path, "%q is already listed at %s", login, first)
+			return
+		}
+		seen[strings.ToLower(login)] = formatPath(path)
+	}
+
+	for i, u := range c.Users {
+		login([]interface{}{"users", i}, u.Login)
+		for j, alias := range u.Aliases {
+			login([]interface{}{"users", i, "aliases", j}, alias)
+		}
+
+		date := func(key, value string) time.Time {
+			if value == "" {
+				return time.Time{}
+			}
+			t, err := time.Parse(dateLayout, value)
+			if err != nil {
+				errs.add(l, []interface{}{"users", i, key}, "%q is not a YYYY-MM-DD date", value)
+			}
+			return t
+		}
+		from := date("active_from", u.ActiveFrom)
+		to := date("active_to", u.ActiveTo)
+		if !from.IsZero() && !to.IsZero() && to.Before(from) {
+			errs.add(l, []interface{}{"users", i, "active_to"}, "%s is before active_from %s", u.ActiveTo, u.ActiveFrom)
+		}
+
+		if u.Avatar != "" {
+			if a, err := url.Parse(u.Avatar); err != nil || (a.Scheme != "http" && a.Scheme != "https") || a.Host == "" {
+				errs.add(l, []interface{}{"users", i, "avatar"}, "%q is not an http(s) URL", u.Avatar)
+			}
+		}
+	}
+}
+
+func (c *Config) hasTeamMembers() bool {
+	for _, team := range c.Teams {
+		if len(team.AllMembers()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLogins reports invalid and repeated names in the list found at path.
+func checkLogins(l configLocator, errs *ConfigErrors, path []interface{}, logins []string) {
+	seen := make(map[string]int)
+	for i, login := range logins {
+		at := append(append([]interface{}(nil), path...), i)
+		if !githubLogin.MatchString(login) {
+			errs.add(l, at, "%q is not a valid GitHub name", login)
+			continue
+		}
+		if first, ok := seen[strings.ToLower(login)]; ok {
+			errs.add(l, at, "%q is already listed at %s", login, formatPath(append(append([]interface{}(nil), path...), first)))
+			continue
+		}
+		seen[strings.ToLower(login)] = i
+	}
+}
+
+// CheckConfigOnGitHub confirms every configured user, org and GitHub team exists on GitHub.
+// It returns one error per missing account, or the first error talking to GitHub.
+func CheckConfigOnGitHub(ctx context.Context, config *Config, token string) ([]error, error) {
+	var problems []error
+	check := func(kind, path, name string) error {
+		var account struct {
+			Type string `json:"type"`
+		}
+		err := getGitHubJSON(ctx, "https://api.github.com/"+path, token, &account)
+		var ghErr *GitHubError
+		if errors.As(err, &ghErr) && ghErr.StatusCode == http.StatusNotFound {
+			problems = append(problems, fmt.Errorf("%s %q does not exist on GitHub", kind, name))
+			return nil
+		}
+		if err == nil && kind == "user" && account.Type == "Organization" {
+			problems = append(problems, fmt.Errorf("user %q is an organization; list it under orgs", name))
+		}
+		return err
+	}
+
+	for _, u := range config.Users {
+		for _, user := range u.Logins() {
+			if err := check("user", "users/"+url.PathEscape(user), user); err != nil {
+				return problems, err
+			}
+		}
+	}
+	for _, org := range config.Orgs {
+		if err := check("org", "orgs/"+url.PathEscape(org), org); err != nil {
+			return problems, err
+		}
+	}
+
+	teams := append([]string(nil), config.GitHubTeams...)
+	for _, team := range config.Teams {
+		if team.GitHubTeam != "" {
+			teams = append(teams, team.GitHubTeam)
+		}
+	}
+	for _, team := range teams {
+		org, slug, _ := strings.Cut(team, "/")
+		if err := check("team", "orgs/"+url.PathEscape(org)+"/teams/"+url.PathEscape(slug), team); err != nil {
+			return problems, err
+		}
+	}
+	return problems, nil
+}
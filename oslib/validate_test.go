@@ -0,0 +1,80 @@
+package oslib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigErrorLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		config string
+		want   []string
+	}{
+		{
+			name:   "unknown field",
+			config: "users: [octocat]\norgs: [kubernetes]\nsite_ulr: https://example.com/\n",
+			want:   []string{"config.yaml:3: site_ulr: unknown field"},
+		},
+		{
+			name:   "unknown nested field",
+			config: "users: [octocat]\norgs: [kubernetes]\nlabels:\n  - name: bug\n    unasigned: true\n",
+			want:   []string{"config.yaml:5: labels[0].unasigned: unknown field"},
+		},
+		{
+			name:   "every problem at its line",
+			config: "users:\n  - octocat\n  - not a login\norgs: [kubernetes]\nstale:\n  days_since_update: -1\n",
+			want: []string{
+				`config.yaml:3: users[1]: "not a login" is not a valid GitHub name`,
+				"config.yaml:6: stale.days_since_update: must not be negative",
+			},
+		},
+		{
+			name:   "duplicate label file",
+			config: "users: [octocat]\norgs: [kubernetes]\nlabels:\n  - name: help wanted\n  - name: Help Wanted\n",
+			want:   []string{`config.yaml:5: labels[1]: writes to "help_wanted", like labels[0]; set a different file`},
+		},
+		{
+			name:   "value from the environment",
+			env:    map[string]string{EnvSiteURL: "ftp://example.com/"},
+			config: "users: [octocat]\norgs: [kubernetes]\n",
+			want:   []string{`TRACKER_SITE_URL: site_url: "ftp://example.com/" is not an http(s) URL`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadConfig(path)
+			if err == nil {
+				t.Fatal("LoadConfig() succeeded, want errors")
+			}
+			got := strings.ReplaceAll(err.Error(), path, "config.yaml")
+			if want := strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("LoadConfig() error =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigValid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"users": ["octocat"], "orgs": ["kubernetes"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Labels) != len(defaultLabels) {
+		t.Errorf("got %d labels, want the %d defaults", len(config.Labels), len(defaultLabels))
+	}
+}
@@ -0,0 +1,190 @@
+package oslib
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Why an open issue or PR is waiting on a user.
+const (
+	WaitReviewRequested = "review requested"
+	WaitMentioned       = "mentioned"
+	WaitAssigned        = "assigned"
+)
+
+// WaitingItem is an open issue or PR that asks something of a user.
+type WaitingItem struct {
+	Issue   Issue    `json:"issue"`
+	Reasons []string `json:"reasons"`
+	// LastActor commented or reviewed last, at LastActionAt. Both are empty when nobody has.
+	LastActor    string `json:"last_actor,omitempty"`
+	LastActionAt string `json:"last_action_at,omitempty"`
+}
+
+// waitingSince is when the ball last moved: the last action, or the item's creation.
+func (w WaitingItem) waitingSince() string {
+	if w.LastActionAt != "" {
+		return w.LastActionAt
+	}
+	return w.Issue.CreatedAt
+}
+
+// lastActor is who acted last, counting the author as having acted by opening the item.
+func (w WaitingItem) lastActor() string {
+	if w.LastActor != "" {
+		return w.LastActor
+	}
+	return w.Issue.Author()
+}
+
+func reviewRequestedQuery(username string) SearchQuery {
+	return NewSearchQuery().With("review-requested", username).Is("pr").State("open")
+}
+
+// mentionsQuery only looks at the last three months; older mentions on open items are rarely still a question.
+func mentionsQuery(username string) SearchQuery {
+	threeMonthsAgo := time.Now().AddDate(0, -3, 0)
+	return NewSearchQuery().With("mentions", username).State("open").Updated(Since(threeMonthsAgo))
+}
+
+func assignedPRsQuery(username string) SearchQuery {
+	return NewSearchQuery().Assignee(username).Is("pr").State("open")
+}
+
+// fetchWaiting runs the searches for each reason and looks up who acted last on every item found.
+func fetchWaiting(ctx context.Context, queries map[string]SearchQuery, token string) ([]WaitingItem, error) {
+	var items []WaitingItem
+	for _, reason := range []string{WaitReviewRequested, WaitMentioned, WaitAssigned} {
+		q, ok := queries[reason]
+		if !ok {
+			continue
+		}
+		issues, err := searchIssues(ctx, q, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			upsertWaiting(&items, WaitingItem{Issue: issue, Reasons: []string{reason}})
+		}
+	}
+	for i := range items {
+		actor, at, err := fetchLastAction(ctx, items[i].Issue, token)
+		if err != nil {
+			return nil, fmt.Errorf("fetching the last action on %s: %w", items[i].Issue.URL, err)
+		}
+		items[i].LastActor, items[i].LastActionAt = actor, at
+	}
+	return items, nil
+}
+
+// fetchLastAction finds the latest comment, or review on a PR, and who made it.
+func fetchLastAction(ctx context.Context, issue Issue, token string) (actor, at string, err error) {
+	comments, err := fetchIssueComments(ctx, issue, "", token)
+	if err != nil {
+		return "", "", err
+	}
+	for _, c := range comments {
+		if c.CreatedAt > at {
+			actor, at = c.User.Login, c.CreatedAt
+		}
+	}
+	if issue.PullRequest == nil {
+		return actor, at, nil
+	}
+	reviews, err := fetchPRReviews(ctx, issue, token)
+	if err != nil {
+		return "", "", err
+	}
+	for _, r := range reviews {
+		if r.SubmittedAt > at {
+			actor, at = r.User.Login, r.SubmittedAt
+		}
+	}
+	return actor, at, nil
+}
+
+// upsertWaiting adds w, or adds its reasons to the item already listed for
+// the same issue, keeping the later of their last actions.
+func upsertWaiting(list *[]WaitingItem, w WaitingItem) {
+	for i, existing := range *list {
+		if existing.Issue.URL != w.Issue.URL {
+			continue
+		}
+		for _, reason := range w.Reasons {
+			if !containsFold(existing.Reasons, reason) {
+				existing.Reasons = append(existing.Reasons[:len(existing.Reasons):len(existing.Reasons)], reason)
+			}
+		}
+		if w.LastActionAt > existing.LastActionAt {
+			existing.LastActor, existing.LastActionAt = w.LastActor, w.LastActionAt
+		}
+		(*list)[i] = existing
+		return
+	}
+	*list = append(*list, w)
+}
+
+// waitingOnMe is the user's to-do list: pending review requests, plus
+// mentions and assigned PRs where someone else acted last. Longest waiting first.
+func (d *Dataset) waitingOnMe(user string) []WaitingItem {
+	person := d.person(user)
+	var items []WaitingItem
+	for _, w := range d.user(user).Waiting {
+		if containsFold(w.Reasons, WaitReviewRequested) || !person.Has(w.lastActor()) {
+			items = append(items, w)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].waitingSince() < items[j].waitingSince()
+	})
+	return items
+}
+
+// recordAction notes that login commented on or reviewed issue, wherever it is on a to-do list.
+func (d *Dataset) recordAction(issue Issue, login, at string) {
+	for _, data := range d.UserData {
+		for i := range data.Waiting {
+			w := &data.Waiting[i]
+			if w.Issue.URL == issue.URL && at >= w.LastActionAt {
+				w.LastActor, w.LastActionAt = login, at
+			}
+		}
+	}
+}
+
+// dropReviewRequest removes the review request reason from a to-do item once
+// the user reviewed, as GitHub does. The item goes when it has no reason left.
+func dropReviewRequest(data *UserData, url string) {
+	kept := data.Waiting[:0]
+	for _, w := range data.Waiting {
+		if w.Issue.URL == url {
+			var reasons []string
+			for _, reason := range w.Reasons {
+				if reason != WaitReviewRequested {
+					reasons = append(reasons, reason)
+				}
+			}
+			if reasons == nil {
+				continue
+			}
+			w.Reasons = reasons
+		}
+		kept = append(kept, w)
+	}
+	data.Waiting = kept
+}
+
+// removeWaiting drops a closed issue or PR from every to-do list.
+func (d *Dataset) removeWaiting(url string) {
+	for _, data := range d.UserData {
+		kept := data.Waiting[:0]
+		for _, w := range data.Waiting {
+			if w.Issue.URL != url {
+				kept = append(kept, w)
+			}
+		}
+		data.Waiting = kept
+	}
+}
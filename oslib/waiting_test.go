@@ -0,0 +1,52 @@
+package oslib
+
+import "testing"
+
+func TestWaitingOnMe(t *testing.T) {
+	item := func(url, author, lastActor, lastAt, created string, reasons ...string) WaitingItem {
+		return WaitingItem{
+			Issue:        Issue{URL: url, CreatedAt: created, User: &UserRef{Login: author}},
+			Reasons:      reasons,
+			LastActor:    lastActor,
+			LastActionAt: lastAt,
+		}
+	}
+	d := &Dataset{
+		Users:  []string{"octocat"},
+		People: map[string]User{"octocat": {Login: "octocat", Aliases: []string{"octo-work"}}},
+		UserData: map[string]*UserData{"octocat": {Waiting: []WaitingItem{
+			item("replied", "alice", "bob", "2026-10-10T00:00:00Z", "2026-10-01T00:00:00Z", WaitMentioned),
+			item("answered", "alice", "octocat", "2026-10-11T00:00:00Z", "2026-10-01T00:00:00Z", WaitMentioned),
+			item("answered-by-alias", "alice", "OCTO-WORK", "2026-10-11T00:00:00Z", "2026-10-01T00:00:00Z", WaitAssigned),
+			item("own-untouched", "octocat", "", "", "2026-09-01T00:00:00Z", WaitAssigned),
+			item("review-requested", "alice", "octocat", "2026-10-12T00:00:00Z", "2026-10-01T00:00:00Z", WaitReviewRequested),
+			item("untouched", "alice", "", "", "2026-10-05T00:00:00Z", WaitAssigned),
+		}}},
+	}
+	var got []string
+	for _, w := range d.waitingOnMe("octocat") {
+		got = append(got, w.Issue.URL)
+	}
+	// Longest waiting first: the untouched item since it was opened, then by last action.
+	want := []string{"untouched", "replied", "review-requested"}
+	if len(got) != len(want) {
+		t.Fatalf("waitingOnMe() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("waitingOnMe() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestUpsertWaiting(t *testing.T) {
+	var list []WaitingItem
+	upsertWaiting(&list, WaitingItem{Issue: Issue{URL: "u"}, Reasons: []string{WaitMentioned}, LastActor: "alice", LastActionAt: "2026-10-01T00:00:00Z"})
+	upsertWaiting(&list, WaitingItem{Issue: Issue{URL: "u"}, Reasons: []string{WaitAssigned, WaitMentioned}, LastActor: "bob", LastActionAt: "2026-10-02T00:00:00Z"})
+	if len(list) != 1 {
+		t.Fatalf("got %d items, want the two merged", len(list))
+	}
+	if w := list[0]; len(w.Reasons) != 2 || w.LastActor != "bob" {
+		t.Errorf("merged item = %+v, want both reasons and the later action", w)
+	}
+}
@@ -0,0 +1,454 @@
+package oslib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// WebhookSecretEnv names the environment variable holding the secret
+// configured on the GitHub webhook. Without it the webhook endpoint is disabled.
+const WebhookSecretEnv = "GITHUB_WEBHOOK_SECRET"
+
+// maxWebhookBody is GitHub's own cap on delivery size.
+const maxWebhookBody = 25 << 20
+
+type webhookPayload struct {
+	Action      string        `json:"action"`
+	Issue       *webhookIssue `json:"issue"`
+	PullRequest *webhookIssue `json:"pull_request"`
+	Review      *prReview     `json:"review"`
+	Comment     *issueComment `json:"comment"`
+	Repository  struct {
+		Name  string       `json:"name"`
+		Owner webhookLogin `json:"owner"`
+	} `json:"repository"`
+}
+
+type webhookLogin struct {
+	Login string `json:"login"`
+}
+
+// webhookIssue covers both the issue and the pull_request objects of a delivery.
+type webhookIssue struct {
+	Number    int            `json:"number"`
+	Title     string         `json:"title"`
+	URL       string         `json:"html_url"`
+	State     string         `json:"state"`
+	CreatedAt string         `json:"created_at"`
+	UpdatedAt string         `json:"updated_at"`
+	ClosedAt  string         `json:"closed_at"`
+	MergedAt  string         `json:"merged_at"`
+	Draft     bool           `json:"draft"`
+	User      webhookLogin   `json:"user"`
+	Assignees []webhookLogin `json:"assignees"`
+	Labels    []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	// PullRequest is set on issue objects that are really pull requests.
+	PullRequest *PullRequestRef `json:"pull_request"`
+}
+
+func (wi *webhookIssue) toIssue(owner, repo string, isPR bool) Issue {
+	issue := Issue{
+		Number:    wi.Number,
+		Title:     wi.Title,
+		URL:       wi.URL,
+		Repo:      repo,
+		Owner:     owner,
+		CreatedAt: wi.CreatedAt,
+		UpdatedAt: wi.UpdatedAt,
+		ClosedAt:  wi.ClosedAt,
+		User:      &UserRef{Login: wi.User.Login},
+	}
+	for _, l := range wi.Labels {
+		issue.Labels = append(issue.Labels, IssueLabel{Name: l.Name})
+	}
+	if isPR {
+		issue.PullRequest = &PullRequestRef{MergedAt: wi.MergedAt}
+		issue.Draft = wi.Draft
+	}
+	return issue
+}
+
+func (wi *webhookIssue) hasLabel(name string) bool {
+	for _, l := range wi.Labels {
+		if strings.EqualFold(l.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (wi *webhookIssue) assignedTo(u User) bool {
+	for _, a := range wi.Assignees {
+		if u.Has(a.Login) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleWebhook verifies a GitHub delivery and applies it to the served dataset right away.
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhookSecret == "" {
+		http.Error(w, "webhooks are not enabled", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "could not read body", http.StatusBadRequest)
+		return
+	}
+	if !validSignature(s.webhookSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	var payload webhookPayload
+	if event != "ping" {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	change := s.webhookChange(event, &payload)
+	if change == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	s.update(change)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func validSignature(secret string, body []byte, header string) bool {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// webhookChange maps a delivery onto a change to the dataset, or nil when there is nothing to track.
+func (s *Server) webhookChange(event string, p *webhookPayload) func(d *Dataset) {
+	owner := p.Repository.Owner.Login
+	repo := p.Repository.Name
+
+	switch event {
+	case "issues":
+		if p.Issue == nil {
+			return nil
+		}
+		return func(d *Dataset) { d.applyIssue(s.config, owner, repo, p.Issue) }
+	case "pull_request":
+		if p.PullRequest == nil {
+			return nil
+		}
+		return func(d *Dataset) { d.applyPR(owner, repo, p.PullRequest) }
+	case "pull_request_review":
+		if p.PullRequest == nil {
+			return nil
+		}
+		return func(d *Dataset) { d.applyReview(owner, repo, p.PullRequest, p.Review) }
+	case "issue_comment":
+		if p.Issue == nil {
+			return nil
+		}
+		return func(d *Dataset) {
+			issue := p.Issue.toIssue(owner, repo, p.Issue.PullRequest != nil)
+			d.touch(issue)
+			if p.Action == "created" {
+				d.applyComment(issue, p.Comment)
+				d.applyClaim(issue, p.Comment)
+			}
+		}
+	}
+	return nil
+}
+
+// applyIssue re-buckets an issue for every tracked user and configured label it concerns.
+func (d *Dataset) applyIssue(config *Config, owner, repo string, wi *webhookIssue) {
+	issue := wi.toIssue(owner, repo, false)
+	open := wi.State == "open"
+	if !open {
+		d.removeWaiting(issue.URL)
+	}
+
+	for _, user := range d.Users {
+		data := d.UserData[user]
+		if data == nil {
+			continue
+		}
+		removeIssue(&data.CreatedIssues, issue.URL)
+		removeIssue(&data.ClosedIssues, issue.URL)
+		removeIssue(&data.AssignedIssues, issue.URL)
+
+		person := d.person(user)
+		if person.Has(wi.User.Login) {
+			if open {
+				upsertIssue(&data.CreatedIssues, issue)
+			} else {
+				upsertIssue(&data.ClosedIssues, issue)
+			}
+		}
+		if open && wi.assignedTo(person) {
+			upsertIssue(&data.AssignedIssues, issue)
+		}
+	}
+
+	if config == nil {
+		return
+	}
+	org, ok := matchFold(config.Orgs, owner)
+	if !ok {
+		return
+	}
+	for _, l := range d.Labels {
+		issues := l.ByOrg[org]
+		removeIssue(&issues, issue.URL)
+		// Linked PRs are not part of the payload; they drop out on the next refresh.
+		if open && wi.hasLabel(l.Label.Name) && (!l.Label.Unassigned || len(wi.Assignees) == 0) {
+			upsertIssue(&issues, issue)
+		} else {
+			delete(l.Claims, issue.URL)
+		}
+		l.ByOrg[org] = issues
+	}
+}
+
+// applyPR re-buckets a pull request under its author, if tracked.
+func (d *Dataset) applyPR(owner, repo string, wi *webhookIssue) {
+	pr := wi.toIssue(owner, repo, true)
+	if wi.State != "open" {
+		d.removeWaiting(pr.URL)
+	}
+
+	for _, user := range d.Users {
+		data := d.UserData[user]
+		if data == nil || !d.person(user).Has(wi.User.Login) {
+			continue
+		}
+		removeIssue(&data.OpenPRs, pr.URL)
+		removeIssue(&data.ClosedPRs, pr.URL)
+		if wi.State == "open" {
+			upsertIssue(&data.OpenPRs, pr)
+			if status, ok := data.PRStatus[pr.URL]; ok {
+				status.Draft = wi.Draft
+				data.PRStatus[pr.URL] = status
+			}
+		} else {
+			upsertIssue(&data.ClosedPRs, pr)
+			delete(data.PRStatus, pr.URL)
+		}
+		if containsFold(kubernetesOrgs, owner) {
+			upsertIssue(&data.KubernetesPRs, pr)
+		}
+	}
+}
+
+// applyReview records a submitted review for its reviewer, if tracked, and refreshes the PR.
+func (d *Dataset) applyReview(owner, repo string, wi *webhookIssue, review *prReview) {
+	pr := wi.toIssue(owner, repo, true)
+	d.touch(pr)
+	if review == nil || !containsFold(reviewStates, review.State) {
+		return
+	}
+	d.recordAction(pr, review.User.Login, review.SubmittedAt)
+	if strings.EqualFold(review.User.Login, wi.User.Login) {
+		return
+	}
+	d.recordReply(pr, review.SubmittedAt)
+	for _, user := range d.Users {
+		data := d.UserData[user]
+		if data == nil || !d.person(user).Has(review.User.Login) {
+			continue
+		}
+		dropReviewRequest(data, pr.URL)
+		if d.trackReviews {
+			upsertReview(&data.Reviews, Review{PR: pr, State: strings.ToUpper(review.State), SubmittedAt: review.SubmittedAt})
+		}
+	}
+}
+
+// applyComment records a new comment for its author, if tracked.
+func (d *Dataset) applyComment(issue Issue, comment *issueComment) {
+	if comment == nil {
+		return
+	}
+	d.recordAction(issue, comment.User.Login, comment.CreatedAt)
+	if !strings.EqualFold(comment.User.Login, issue.Author()) {
+		d.recordReply(issue, comment.CreatedAt)
+	}
+	if !d.trackComments {
+		return
+	}
+	for _, user := range d.Users {
+		data := d.UserData[user]
+		if data == nil || !d.person(user).Has(comment.User.Login) {
+			continue
+		}
+		upsertComment(&data.Comments, Comment{Issue: issue, URL: comment.URL, CreatedAt: comment.CreatedAt})
+	}
+}
+
+// recordReply notes someone other than its author replied on issue, wherever staleness is tracked for it.
+func (d *Dataset) recordReply(issue Issue, at string) {
+	for _, data := range d.UserData {
+		if data.LastReplies == nil {
+			continue
+		}
+		for _, list := range [][]Issue{data.OpenPRs, data.AssignedIssues} {
+			for _, tracked := range list {
+				if tracked.URL == issue.URL {
+					data.LastReplies[issue.URL] = max(data.LastReplies[issue.URL], at)
+				}
+			}
+		}
+	}
+}
+
+// touch refreshes the title and update time of an already tracked issue or PR without moving it.
+func (d *Dataset) touch(issue Issue) {
+	update := func(list []Issue) {
+		for i := range list {
+			if list[i].URL == issue.URL {
+				list[i].Title = issue.Title
+				list[i].UpdatedAt = issue.UpdatedAt
+			}
+		}
+	}
+	for _, data := range d.UserData {
+		update(data.AssignedIssues)
+		update(data.CreatedIssues)
+		update(data.ClosedIssues)
+		update(data.OpenPRs)
+		update(data.ClosedPRs)
+		update(data.KubernetesPRs)
+		for i := range data.Reviews {
+			if data.Reviews[i].PR.URL == issue.URL {
+				data.Reviews[i].PR.Title = issue.Title
+				data.Reviews[i].PR.UpdatedAt = issue.UpdatedAt
+			}
+		}
+		for i := range data.Comments {
+			if data.Comments[i].Issue.URL == issue.URL {
+				data.Comments[i].Issue.Title = issue.Title
+				data.Comments[i].Issue.UpdatedAt = issue.UpdatedAt
+			}
+		}
+		for i := range data.Waiting {
+			if data.Waiting[i].Issue.URL == issue.URL {
+				data.Waiting[i].Issue.Title = issue.Title
+				data.Waiting[i].Issue.UpdatedAt = issue.UpdatedAt
+			}
+		}
+	}
+	for _, l := range d.Labels {
+		for _, issues := range l.ByOrg {
+			update(issues)
+		}
+	}
+}
+
+// Clone copies the dataset deeply enough that changing the copy's lists and maps leaves d untouched.
+func (d *Dataset) Clone() *Dataset {
+	c := &Dataset{
+		FetchedAt:     d.FetchedAt,
+		Users:         d.Users,
+		Teams:         d.Teams,
+		People:        maps.Clone(d.People),
+		Repos:         maps.Clone(d.Repos),
+		allUsers:      d.allUsers,
+		showCommits:   d.showCommits,
+		showWaiting:   d.showWaiting,
+		trackReviews:  d.trackReviews,
+		trackComments: d.trackComments,
+		cycleTime:     d.cycleTime,
+		stale:         d.stale,
+		scoring:       d.scoring,
+		classes:       d.classes,
+		UserData:      make(map[string]*UserData, len(d.UserData)),
+	}
+	for user, data := range d.UserData {
+		copied := *data
+		copied.AssignedIssues = cloneIssues(data.AssignedIssues)
+		copied.CreatedIssues = cloneIssues(data.CreatedIssues)
+		copied.ClosedIssues = cloneIssues(data.ClosedIssues)
+		copied.OpenPRs = cloneIssues(data.OpenPRs)
+		copied.ClosedPRs = cloneIssues(data.ClosedPRs)
+		copied.KubernetesPRs = cloneIssues(data.KubernetesPRs)
+		copied.Reviews = append([]Review(nil), data.Reviews...)
+		copied.Comments = append([]Comment(nil), data.Comments...)
+		copied.Commits = append([]Commit(nil), data.Commits...)
+		copied.PRCycles = append([]PRCycle(nil), data.PRCycles...)
+		copied.Waiting = append([]WaitingItem(nil), data.Waiting...)
+		copied.PRStatus = maps.Clone(data.PRStatus)
+		copied.LastReplies = maps.Clone(data.LastReplies)
+		copied.PRSizes = maps.Clone(data.PRSizes)
+		c.UserData[user] = &copied
+	}
+	for _, l := range d.Labels {
+		byOrg := make(map[string][]Issue, len(l.ByOrg))
+		for org, issues := range l.ByOrg {
+			byOrg[org] = cloneIssues(issues)
+		}
+		c.Labels = append(c.Labels, LabelIssues{Label: l.Label, ByOrg: byOrg, Claims: maps.Clone(l.Claims)})
+	}
+	return c
+}
+
+func cloneIssues(issues []Issue) []Issue {
+	if issues == nil {
+		return nil
+	}
+	return append([]Issue(nil), issues...)
+}
+
+func removeIssue(list *[]Issue, url string) {
+	kept := (*list)[:0]
+	for _, issue := range *list {
+		if issue.URL != url {
+			kept = append(kept, issue)
+		}
+	}
+	*list = kept
+}
+
+// upsertIssue replaces the entry with the same URL or adds it, keeping the list newest first.
+func upsertIssue(list *[]Issue, issue Issue) {
+	removeIssue(list, issue.URL)
+	*list = append(*list, issue)
+	sort.SliceStable(*list, func(i, j int) bool {
+		return (*list)[i].CreatedAt > (*list)[j].CreatedAt
+	})
+}
+
+func containsFold(list []string, s string) bool {
+	_, ok := matchFold(list, s)
+	return ok
+}
+
+// matchFold finds s in list ignoring case and returns the list's spelling of it.
+func matchFold(list []string, s string) (string, bool) {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return item, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,236 @@
+package oslib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestValidSignature(t *testing.T) {
+	const secret = "s3cret"
+	body := []byte(`{"action":"opened"}`)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"valid", "sha256=" + signature, true},
+		{"wrong", "sha256=" + hex.EncodeToString(make([]byte, sha256.Size)), false},
+		{"not hex", "sha256=zz", false},
+		{"missing prefix", signature, false},
+		{"sha1 prefix", "sha1=" + signature, false},
+		{"empty header", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validSignature(secret, body, tt.header); got != tt.want {
+				t.Errorf("validSignature(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneCopiesMaps(t *testing.T) {
+	d := &Dataset{
+		UserData: map[string]*UserData{"octocat": {PRSizes: map[string]int{}}},
+		Labels:   []LabelIssues{{Claims: map[string]Claim{}}},
+	}
+	c := d.Clone()
+	c.UserData["octocat"].PRSizes["u"] = 10
+	c.Labels[0].Claims["u"] = Claim{By: "someone"}
+	if len(d.UserData["octocat"].PRSizes) != 0 || len(d.Labels[0].Claims) != 0 {
+		t.Errorf("changing the clone changed the original: %v, %v", d.UserData["octocat"].PRSizes, d.Labels[0].Claims)
+	}
+}
+
+func TestWebhookChanges(t *testing.T) {
+	const (
+		issueURL = "https://github.com/o/r/issues/1"
+		prURL    = "https://github.com/o/r/pull/2"
+	)
+	login := func(name string) webhookLogin { return webhookLogin{Login: name} }
+	issue := func(state, author string, assignees ...string) *webhookIssue {
+		wi := &webhookIssue{Number: 1, Title: "Bug", URL: issueURL, State: state, CreatedAt: "2026-10-01T00:00:00Z", User: login(author)}
+		for _, a := range assignees {
+			wi.Assignees = append(wi.Assignees, login(a))
+		}
+		return wi
+	}
+	pr := func(state, author string) *webhookIssue {
+		return &webhookIssue{Number: 2, Title: "Fix", URL: prURL, State: state, CreatedAt: "2026-10-02T00:00:00Z", User: login(author)}
+	}
+	review := func(reviewer, state, at string) *prReview {
+		return &prReview{User: login(reviewer), State: state, SubmittedAt: at}
+	}
+	comment := func(author, at string) *issueComment {
+		return &issueComment{User: login(author), URL: issueURL + "#c" + at, CreatedAt: at}
+	}
+	config := &Config{Orgs: []string{"o"}}
+	urls := func(issues []Issue) []string {
+		var list []string
+		for _, i := range issues {
+			list = append(list, i.URL)
+		}
+		return list
+	}
+
+	tests := []struct {
+		name   string
+		before UserData
+		apply  func(d *Dataset)
+		check  func(t *testing.T, d *Dataset, data *UserData)
+	}{
+		{
+			name:  "opened issue lands in CreatedIssues",
+			apply: func(d *Dataset) { d.applyIssue(config, "o", "r", issue("open", "octocat")) },
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if got := urls(data.CreatedIssues); len(got) != 1 || got[0] != issueURL {
+					t.Errorf("CreatedIssues = %v", got)
+				}
+			},
+		},
+		{
+			name:   "closed issue moves to ClosedIssues and leaves AssignedIssues",
+			before: UserData{CreatedIssues: []Issue{{URL: issueURL}}, AssignedIssues: []Issue{{URL: issueURL}}},
+			apply:  func(d *Dataset) { d.applyIssue(config, "o", "r", issue("closed", "octocat", "octocat")) },
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if len(data.CreatedIssues) != 0 || len(data.AssignedIssues) != 0 || len(data.ClosedIssues) != 1 {
+					t.Errorf("created %v, assigned %v, closed %v", urls(data.CreatedIssues), urls(data.AssignedIssues), urls(data.ClosedIssues))
+				}
+			},
+		},
+		{
+			name:  "issue assigned to a tracked user lands in AssignedIssues only",
+			apply: func(d *Dataset) { d.applyIssue(config, "o", "r", issue("open", "alice", "octocat")) },
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if len(data.AssignedIssues) != 1 || len(data.CreatedIssues) != 0 {
+					t.Errorf("assigned %v, created %v", urls(data.AssignedIssues), urls(data.CreatedIssues))
+				}
+			},
+		},
+		{
+			name: "labelled issue joins the label list",
+			apply: func(d *Dataset) {
+				wi := issue("open", "alice")
+				wi.Labels = append(wi.Labels, struct {
+					Name string `json:"name"`
+				}{"Help Wanted"})
+				d.applyIssue(config, "o", "r", wi)
+			},
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if got := urls(d.Labels[0].ByOrg["o"]); len(got) != 1 || got[0] != issueURL {
+					t.Errorf("label issues = %v", got)
+				}
+			},
+		},
+		{
+			name:   "closed PR moves from OpenPRs to ClosedPRs",
+			before: UserData{OpenPRs: []Issue{{URL: prURL}}, PRStatus: map[string]PRStatus{prURL: {CI: CISuccess}}},
+			apply:  func(d *Dataset) { d.applyPR("o", "r", pr("closed", "octocat")) },
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if len(data.OpenPRs) != 0 || len(data.ClosedPRs) != 1 {
+					t.Errorf("open %v, closed %v", urls(data.OpenPRs), urls(data.ClosedPRs))
+				}
+				if _, ok := data.PRStatus[prURL]; ok {
+					t.Errorf("closed PR kept its status")
+				}
+			},
+		},
+		{
+			name:   "draft PR updates its status",
+			before: UserData{OpenPRs: []Issue{{URL: prURL}}, PRStatus: map[string]PRStatus{prURL: {CI: CISuccess}}},
+			apply: func(d *Dataset) {
+				wi := pr("open", "octocat")
+				wi.Draft = true
+				d.applyPR("o", "r", wi)
+			},
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if !data.PRStatus[prURL].Draft || len(data.OpenPRs) != 1 {
+					t.Errorf("status %+v, open %v", data.PRStatus[prURL], urls(data.OpenPRs))
+				}
+			},
+		},
+		{
+			name: "PR by someone else is ignored",
+			apply: func(d *Dataset) {
+				d.applyPR("o", "r", pr("open", "alice"))
+			},
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if len(data.OpenPRs) != 0 {
+					t.Errorf("open %v", urls(data.OpenPRs))
+				}
+			},
+		},
+		{
+			name:   "review is upserted and answers the review request",
+			before: UserData{Waiting: []WaitingItem{{Issue: Issue{URL: prURL}, Reasons: []string{WaitReviewRequested}}}},
+			apply: func(d *Dataset) {
+				d.applyReview("o", "r", pr("open", "alice"), review("octocat", "approved", "2026-10-03T00:00:00Z"))
+				d.applyReview("o", "r", pr("open", "alice"), review("octocat", "approved", "2026-10-03T00:00:00Z"))
+			},
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if len(data.Reviews) != 1 || data.Reviews[0].State != "APPROVED" {
+					t.Errorf("reviews = %+v", data.Reviews)
+				}
+				if len(data.Waiting) != 0 {
+					t.Errorf("waiting = %+v", data.Waiting)
+				}
+			},
+		},
+		{
+			name: "review of one's own PR is ignored",
+			apply: func(d *Dataset) {
+				d.applyReview("o", "r", pr("open", "octocat"), review("octocat", "commented", "2026-10-03T00:00:00Z"))
+			},
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if len(data.Reviews) != 0 {
+					t.Errorf("reviews = %+v", data.Reviews)
+				}
+			},
+		},
+		{
+			name: "comments count once per thread and day",
+			apply: func(d *Dataset) {
+				i := issue("open", "alice").toIssue("o", "r", false)
+				d.applyComment(i, comment("octocat", "2026-10-03T10:00:00Z"))
+				d.applyComment(i, comment("octocat", "2026-10-03T11:00:00Z"))
+				d.applyComment(i, comment("octocat", "2026-10-04T09:00:00Z"))
+			},
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if len(data.Comments) != 2 {
+					t.Errorf("comments = %+v", data.Comments)
+				}
+			},
+		},
+		{
+			name: "comment by an untracked user is ignored",
+			apply: func(d *Dataset) {
+				d.applyComment(issue("open", "alice").toIssue("o", "r", false), comment("alice", "2026-10-03T10:00:00Z"))
+			},
+			check: func(t *testing.T, d *Dataset, data *UserData) {
+				if len(data.Comments) != 0 {
+					t.Errorf("comments = %+v", data.Comments)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.before
+			d := &Dataset{
+				Users:         []string{"octocat"},
+				UserData:      map[string]*UserData{"octocat": &before},
+				Labels:        []LabelIssues{{Label: Label{Name: "help wanted"}, ByOrg: map[string][]Issue{}}},
+				trackReviews:  true,
+				trackComments: true,
+			}
+			tt.apply(d)
+			tt.check(t, d, d.UserData["octocat"])
+		})
+	}
+}